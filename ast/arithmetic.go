@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// Arithmetic applies an arithmetic operator to the values of two expressions.
+type Arithmetic struct {
+	Left     Expression
+	Operator Operator
+	Right    Expression
+}
+
+var Arithmetics = map[Operator]func(Value, Value) (Value, error){
+	ADD_Operator:    add,
+	SUB_Operator:    sub,
+	MUL_Operator:    mul,
+	DIV_Operator:    div,
+	INTDIV_Operator: intdiv,
+	MOD_Operator:    mod,
+	POW_Operator:    pow,
+}
+
+func (a *Arithmetic) Evaluate(environment *Environment) (interface{}, error) {
+	left, err := arithmeticOperand(environment, a.Left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := arithmeticOperand(environment, a.Right)
+	if err != nil {
+		return nil, err
+	}
+	// Arithmetic with any other null is null.
+	if isNull(left) || isNull(right) {
+		return NewNullValue(), nil
+	}
+	return Arithmetics[a.Operator](left, right)
+}
+
+func (a *Arithmetic) String() string {
+	return fmt.Sprintf("(%s %s %s)", a.Left, a.Operator, a.Right)
+}
+
+// arithmeticOperand evaluates an operand of an arithmetic expression. A
+// variable that hasn't been assigned yet is 0, so a count or a total can be
+// kept without setting it to 0 first, `count = count + 1`.
+func arithmeticOperand(environment *Environment, expression Expression) (Value, error) {
+	if v, ok := expression.(*VarValue); ok && environment.isUnset(v) {
+		return &IntegerValue{"0", 0}, nil
+	}
+	return evaluateValue(environment, expression)
+}
+
+//
+// Negation Expression
+//
+type negationExpression struct {
+	expression Expression
+}
+
+// NewNegationExpression creates the arithmetic negation of an expression, as
+// in `-%3`.
+func NewNegationExpression(expression Expression) Expression {
+	return &negationExpression{
+		expression: expression,
+	}
+}
+
+func (e *negationExpression) Evaluate(environment *Environment) (interface{}, error) {
+	v, err := evaluateValue(environment, e.expression)
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := v.(*DurationValue); ok {
+		return toValue(d.value.Negate())
+	}
+	if isNull(v) {
+		return v, nil
+	}
+	return sub(&IntegerValue{raw: "0", value: 0}, v)
+}
+
+func (e *negationExpression) String() string {
+	return fmt.Sprintf("-%s", e.expression)
+}
@@ -0,0 +1,336 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/jacobsimpson/jt/datetime"
+	"github.com/shopspring/decimal"
+)
+
+// The numeric types form a simple lattice. Two integers produce an integer,
+// unless the result overflows an int64, in which case it is promoted to a
+// decimal. If either side is a decimal, both sides are promoted to decimal.
+// An AnyValue is parsed as an integer if it can be, or a decimal otherwise,
+// which has the effect of coercing it to the type of the other operand. An
+// empty AnyValue, like a missing column, counts as 0.
+
+func add(left, right Value) (Value, error) {
+	if temporal(left) || temporal(right) {
+		return addTemporal(left, right)
+	}
+	return arithmetic(left, right,
+		func(l, r int64) (Value, bool) {
+			s := l + r
+			return newInteger(s), (s > l) == (r > 0)
+		},
+		func(l, r decimal.Decimal) (Value, error) {
+			return newDouble(l.Add(r)), nil
+		})
+}
+
+func sub(left, right Value) (Value, error) {
+	if temporal(left) || temporal(right) {
+		return subTemporal(left, right)
+	}
+	return arithmetic(left, right,
+		func(l, r int64) (Value, bool) {
+			s := l - r
+			return newInteger(s), (s < l) == (r > 0)
+		},
+		func(l, r decimal.Decimal) (Value, error) {
+			return newDouble(l.Sub(r)), nil
+		})
+}
+
+func mul(left, right Value) (Value, error) {
+	return arithmetic(left, right,
+		func(l, r int64) (Value, bool) {
+			p, ok := mulInt64(l, r)
+			return newInteger(p), ok
+		},
+		func(l, r decimal.Decimal) (Value, error) {
+			return newDouble(l.Mul(r)), nil
+		})
+}
+
+// div always produces a decimal, so that 3 / 2 is 1.5. Use `\` for integer
+// division.
+func div(left, right Value) (Value, error) {
+	l, r, err := decimals(left, right)
+	if err != nil {
+		return nil, err
+	}
+	if r.IsZero() {
+		return nil, fmt.Errorf("division by zero: %s / %s", left, right)
+	}
+	return newDouble(l.Div(r)), nil
+}
+
+// intdiv divides and truncates the result toward zero, so that 3 \ 2 is 1.
+func intdiv(left, right Value) (Value, error) {
+	return arithmetic(left, right,
+		func(l, r int64) (Value, bool) {
+			if r == 0 || (l == math.MinInt64 && r == -1) {
+				return nil, false
+			}
+			return newInteger(l / r), true
+		},
+		func(l, r decimal.Decimal) (Value, error) {
+			if r.IsZero() {
+				return nil, fmt.Errorf("division by zero: %s \\ %s", l, r)
+			}
+			return newDouble(l.Div(r).Truncate(0)), nil
+		})
+}
+
+// mod is the remainder of truncated division, so the result has the sign of
+// the left operand.
+func mod(left, right Value) (Value, error) {
+	return arithmetic(left, right,
+		func(l, r int64) (Value, bool) {
+			if r == 0 || r == -1 {
+				return nil, false
+			}
+			return newInteger(l % r), true
+		},
+		func(l, r decimal.Decimal) (Value, error) {
+			if r.IsZero() {
+				return nil, fmt.Errorf("division by zero: %s %% %s", l, r)
+			}
+			return newDouble(l.Mod(r)), nil
+		})
+}
+
+// pow raises the left operand to the power of the right operand. An integer
+// raised to a non-negative integer power is an integer, anything else is a
+// decimal.
+func pow(left, right Value) (Value, error) {
+	return arithmetic(left, right,
+		func(l, r int64) (Value, bool) {
+			if r < 0 {
+				return nil, false
+			}
+			result, base, ok := int64(1), l, true
+			for ; r > 0; r >>= 1 {
+				if r&1 == 1 {
+					if result, ok = mulInt64(result, base); !ok {
+						return nil, false
+					}
+				}
+				if r > 1 {
+					if base, ok = mulInt64(base, base); !ok {
+						return nil, false
+					}
+				}
+			}
+			return newInteger(result), true
+		},
+		func(l, r decimal.Decimal) (Value, error) {
+			if r.Equal(r.Truncate(0)) {
+				if r.IsNegative() {
+					if l.IsZero() {
+						return nil, fmt.Errorf("division by zero: %s ^ %s", l, r)
+					}
+					return newDouble(decimal.New(1, 0).Div(l.Pow(r.Neg()))), nil
+				}
+				return newDouble(l.Pow(r)), nil
+			}
+			// The decimal package can only raise to integer powers, so
+			// fractional powers fall back to floating point.
+			lf, _ := l.Float64()
+			rf, _ := r.Float64()
+			f := math.Pow(lf, rf)
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, fmt.Errorf("%s ^ %s is not a number", l, r)
+			}
+			return newDouble(decimal.NewFromFloat(f)), nil
+		})
+}
+
+// arithmetic coerces the operands to a common numeric type and applies the
+// matching operation. If the integer operation can not produce an integer
+// result, the operation is repeated with decimals.
+func arithmetic(left, right Value,
+	integerOperation func(int64, int64) (Value, bool),
+	decimalOperation func(decimal.Decimal, decimal.Decimal) (Value, error)) (Value, error) {
+
+	l, err := number(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := number(right)
+	if err != nil {
+		return nil, err
+	}
+	if li, ok := l.(*IntegerValue); ok {
+		if ri, ok := r.(*IntegerValue); ok {
+			if result, ok := integerOperation(li.value, ri.value); ok {
+				return result, nil
+			}
+		}
+	}
+	return decimalOperation(toDecimal(l), toDecimal(r))
+}
+
+// decimals coerces both operands to decimals.
+func decimals(left, right Value) (decimal.Decimal, decimal.Decimal, error) {
+	l, err := number(left)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+	r, err := number(right)
+	if err != nil {
+		return decimal.Decimal{}, decimal.Decimal{}, err
+	}
+	return toDecimal(l), toDecimal(r), nil
+}
+
+// number returns the value as either an IntegerValue or a DoubleValue.
+func number(v Value) (Value, error) {
+	switch t := v.(type) {
+	case *IntegerValue, *DoubleValue:
+		return t, nil
+	case *AnyValue:
+		if t.raw == "" {
+			return newInteger(0), nil
+		}
+		if i, err := strconv.ParseInt(t.raw, 10, 64); err == nil {
+			return newInteger(i), nil
+		}
+		if d, err := decimal.NewFromString(t.raw); err == nil {
+			return newDouble(d), nil
+		}
+		return nil, fmt.Errorf("%q is not a number", t.raw)
+	}
+	return nil, fmt.Errorf("can not do arithmetic with %s", v)
+}
+
+func toDecimal(v Value) decimal.Decimal {
+	switch t := v.(type) {
+	case *IntegerValue:
+		return decimal.New(t.value, 0)
+	case *DoubleValue:
+		return *t.value
+	}
+	return decimal.Decimal{}
+}
+
+// mulInt64 multiplies two integers, reporting false if the result overflows.
+func mulInt64(l, r int64) (int64, bool) {
+	if l == 0 || r == 0 {
+		return 0, true
+	}
+	p := l * r
+	if p/r != l || (l == -1 && r == math.MinInt64) || (r == -1 && l == math.MinInt64) {
+		return 0, false
+	}
+	return p, true
+}
+
+func newInteger(i int64) Value {
+	return &IntegerValue{raw: strconv.FormatInt(i, 10), value: i}
+}
+
+func newDouble(d decimal.Decimal) Value {
+	return &DoubleValue{raw: d.String(), value: &d}
+}
+
+// Dates and durations have rules of their own. A duration can be added to, or
+// subtracted from, a date to make a new date. Two durations add up to a
+// duration, and the difference between two dates is a duration. An AnyValue
+// is coerced to a date if it can be, or a duration otherwise, so the
+// difference between two columns of dates is a duration too.
+
+func addTemporal(left, right Value) (Value, error) {
+	l, err := coerceTemporal(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := coerceTemporal(right)
+	if err != nil {
+		return nil, err
+	}
+	switch l := l.(type) {
+	case *DateTimeValue:
+		if r, ok := r.(*DurationValue); ok {
+			return shiftDateTime(l, r.value), nil
+		}
+	case *DurationValue:
+		switch r := r.(type) {
+		case *DateTimeValue:
+			return shiftDateTime(r, l.value), nil
+		case *DurationValue:
+			return toValue(l.value.Add(r.value))
+		}
+	}
+	return nil, fmt.Errorf("can not add %s and %s", left, right)
+}
+
+func subTemporal(left, right Value) (Value, error) {
+	l, err := coerceTemporal(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := coerceTemporal(right)
+	if err != nil {
+		return nil, err
+	}
+	switch l := l.(type) {
+	case *DateTimeValue:
+		switch r := r.(type) {
+		case *DateTimeValue:
+			return toValue(datetime.Between(r.value, l.value))
+		case *DurationValue:
+			return shiftDateTime(l, r.value.Negate()), nil
+		}
+	case *DurationValue:
+		if r, ok := r.(*DurationValue); ok {
+			return toValue(l.value.Add(r.value.Negate()))
+		}
+	}
+	return nil, fmt.Errorf("can not subtract %s from %s", right, left)
+}
+
+// shiftDateTime moves a date/time by a duration. The result keeps the
+// granularity of the original, so 2013T + 1Y is the whole of 2014, unless the
+// duration is more precise, so 2013-06-01T + 2h is the hour 2013-06-01T02.
+func shiftDateTime(v *DateTimeValue, d datetime.Duration) Value {
+	granularity := v.granularity
+	if d.Granularity() < granularity {
+		granularity = d.Granularity()
+	}
+	return newDateTimeValue(datetime.Period{Start: d.AddTo(v.value), Granularity: granularity})
+}
+
+// temporal reports whether the value is a date or a duration, or is an
+// AnyValue that isn't a number, but can be coerced to a date or a duration.
+func temporal(v Value) bool {
+	switch v.(type) {
+	case *DateTimeValue, *DurationValue:
+		return true
+	case *AnyValue:
+		if _, err := number(v); err != nil {
+			_, err := coerceTemporal(v)
+			return err == nil
+		}
+	}
+	return false
+}
+
+func coerceTemporal(v Value) (Value, error) {
+	switch t := v.(type) {
+	case *DateTimeValue, *DurationValue:
+		return t, nil
+	case *AnyValue:
+		if d, err := parseDateTime(t.raw); err == nil {
+			return &DateTimeValue{raw: t.raw, value: d.Start}, nil
+		}
+		if d, err := datetime.ParseDuration(t.raw); err == nil {
+			return &DurationValue{raw: t.raw, value: d}, nil
+		}
+		return nil, fmt.Errorf("%q is not a date or a duration", t.raw)
+	}
+	return nil, fmt.Errorf("can not do date arithmetic with %s", v)
+}
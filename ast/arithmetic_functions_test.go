@@ -0,0 +1,137 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArithmetic(t *testing.T) {
+	tests := []struct {
+		left     Value
+		operator Operator
+		right    Value
+		want     Value
+	}{
+		{&IntegerValue{"3", 3} /*    */, ADD_Operator, &IntegerValue{"10", 10}, &IntegerValue{"13", 13}},
+		{&AnyValue{"3"} /*           */, ADD_Operator, &IntegerValue{"10", 10}, &IntegerValue{"13", 13}},
+		{&AnyValue{"3.5"} /*         */, ADD_Operator, &IntegerValue{"10", 10}, mustDouble(t, "13.5")},
+		{&AnyValue{""} /*            */, ADD_Operator, &IntegerValue{"10", 10}, &IntegerValue{"10", 10}},
+		{&AnyValue{"2"} /*           */, ADD_Operator, &AnyValue{"3"} /*    */, &IntegerValue{"5", 5}},
+		{&IntegerValue{"3", 3} /*    */, ADD_Operator, mustDouble(t, "0.25"), mustDouble(t, "3.25")},
+		{&IntegerValue{"3", 3} /*    */, SUB_Operator, &IntegerValue{"10", 10}, &IntegerValue{"-7", -7}},
+		{&IntegerValue{"4", 4} /*    */, MUL_Operator, mustDouble(t, "1.5"), mustDouble(t, "6")},
+		{&IntegerValue{"3", 3} /*    */, DIV_Operator, &IntegerValue{"2", 2}, mustDouble(t, "1.5")},
+		{&IntegerValue{"4", 4} /*    */, DIV_Operator, &IntegerValue{"2", 2}, mustDouble(t, "2")},
+		{&IntegerValue{"3", 3} /*    */, INTDIV_Operator, &IntegerValue{"2", 2}, &IntegerValue{"1", 1}},
+		{&IntegerValue{"-3", -3} /*  */, INTDIV_Operator, &IntegerValue{"2", 2}, &IntegerValue{"-1", -1}},
+		{mustDouble(t, "7.5") /*     */, INTDIV_Operator, &IntegerValue{"2", 2}, mustDouble(t, "3")},
+		{&IntegerValue{"5", 5} /*    */, MOD_Operator, &IntegerValue{"3", 3}, &IntegerValue{"2", 2}},
+		{&IntegerValue{"-5", -5} /*  */, MOD_Operator, &IntegerValue{"3", 3}, &IntegerValue{"-2", -2}},
+		{mustDouble(t, "5.5") /*     */, MOD_Operator, &IntegerValue{"3", 3}, mustDouble(t, "2.5")},
+		{&IntegerValue{"2", 2} /*    */, POW_Operator, &IntegerValue{"8", 8}, &IntegerValue{"256", 256}},
+		{&IntegerValue{"2", 2} /*    */, POW_Operator, &IntegerValue{"-1", -1}, mustDouble(t, "0.5")},
+		{mustDouble(t, "1.5") /*     */, POW_Operator, &IntegerValue{"2", 2}, mustDouble(t, "2.25")},
+		{&IntegerValue{"4", 4} /*    */, POW_Operator, mustDouble(t, "0.5"), mustDouble(t, "2")},
+		// Integer results that overflow an int64 are promoted to decimal.
+		{&IntegerValue{"9223372036854775807", 9223372036854775807}, ADD_Operator, &IntegerValue{"1", 1}, mustDouble(t, "9223372036854775808")},
+		{&IntegerValue{"2", 2}, POW_Operator, &IntegerValue{"64", 64}, mustDouble(t, "18446744073709551616")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.left.String()+" "+test.operator.String()+" "+test.right.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := Arithmetics[test.operator](test.left, test.right)
+
+			assert.NoError(err)
+			assert.IsType(test.want, got)
+			assert.Equal(test.want.String(), got.String())
+		})
+	}
+}
+
+func TestArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		left     Value
+		operator Operator
+		right    Value
+	}{
+		{&AnyValue{"abc"}, ADD_Operator, &IntegerValue{"1", 1}},
+		{NewStringValue("'1'"), ADD_Operator, &IntegerValue{"1", 1}},
+		{&IntegerValue{"1", 1}, DIV_Operator, &IntegerValue{"0", 0}},
+		{&IntegerValue{"1", 1}, INTDIV_Operator, &IntegerValue{"0", 0}},
+		{&IntegerValue{"1", 1}, MOD_Operator, mustDouble(t, "0.0")},
+		{&IntegerValue{"0", 0}, POW_Operator, &IntegerValue{"-1", -1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.left.String()+" "+test.operator.String()+" "+test.right.String(), func(t *testing.T) {
+			_, err := Arithmetics[test.operator](test.left, test.right)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTemporalArithmetic(t *testing.T) {
+	tests := []struct {
+		left     Value
+		operator Operator
+		right    Value
+		want     string
+	}{
+		{mustDateTime(t, "2020-01-31T"), ADD_Operator, mustDuration(t, "1D"), "2020-02-01T"},
+		{mustDuration(t, "1h"), ADD_Operator, mustDateTime(t, "2020-01-31T"), "2020-01-31T01"},
+		{mustDateTime(t, "2020-03-31T"), SUB_Operator, mustDuration(t, "1Y"), "2019-03-31T"},
+		{mustDateTime(t, "2020T"), ADD_Operator, mustDuration(t, "1Y"), "2021T"},
+		{&AnyValue{"2020-03-31T10:15"}, SUB_Operator, mustDuration(t, "15m"), "2020-03-31T10:00:00"},
+		{mustDateTime(t, "2020-03-02T"), SUB_Operator, mustDateTime(t, "2020-03-01T"), "24h"},
+		{mustDateTime(t, "2020-03-01T"), SUB_Operator, &AnyValue{"2020-03-02T"}, "-24h"},
+		{mustDuration(t, "1Y"), ADD_Operator, mustDuration(t, "2M"), "1Y2M"},
+		{mustDuration(t, "1h"), SUB_Operator, &AnyValue{"15m"}, "45m"},
+		{&AnyValue{"2020-03-01T12:30"}, SUB_Operator, &AnyValue{"2020-03-01T10:00"}, "2h30m"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.left.String()+" "+test.operator.String()+" "+test.right.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := Arithmetics[test.operator](test.left, test.right)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got.String())
+		})
+	}
+}
+
+func TestTemporalArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		left     Value
+		operator Operator
+		right    Value
+	}{
+		{mustDateTime(t, "2020-01-31T"), ADD_Operator, mustDateTime(t, "2020-01-31T")},
+		{mustDuration(t, "1h"), SUB_Operator, mustDateTime(t, "2020-01-31T")},
+		{mustDuration(t, "1h"), ADD_Operator, &IntegerValue{"1", 1}},
+		{mustDuration(t, "1h"), ADD_Operator, &AnyValue{"abc"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.left.String()+" "+test.operator.String()+" "+test.right.String(), func(t *testing.T) {
+			_, err := Arithmetics[test.operator](test.left, test.right)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNegationExpression(t *testing.T) {
+	environment := NewEnvironment()
+	environment.Row = &Row{LineNumber: 1, Columns: []string{"1.5 4", "1.5", "4"}}
+
+	got, err := NewNegationExpression(NewVarValue("%1")).Evaluate(environment)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "-1.5", got.(Value).String())
+}
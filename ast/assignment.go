@@ -0,0 +1,31 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Assignment stores the value of an expression in a variable. The environment
+// is kept from one line of input to the next, so the variable will keep the
+// value until it is assigned again. An assignment to a column, like `%3`,
+// changes the column of the current line instead.
+type Assignment struct {
+	Name  string
+	Value Expression
+}
+
+func (a *Assignment) Evaluate(environment *Environment) (interface{}, error) {
+	v, err := evaluateValue(environment, a.Value)
+	if err != nil {
+		return nil, fmt.Errorf("could not assign %s to %s: %w", a.Value, a.Name, err)
+	}
+	if strings.HasPrefix(a.Name, "%") {
+		return nil, environment.SetColumn(a.Name, v)
+	}
+	environment.Set(a.Name, v)
+	return nil, nil
+}
+
+func (a *Assignment) String() string {
+	return fmt.Sprintf("%s = %s", a.Name, a.Value)
+}
@@ -0,0 +1,84 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignment(t *testing.T) {
+	assert := assert.New(t)
+
+	environment := NewEnvironment()
+	environment.Row = &Row{LineNumber: 1, Columns: []string{"whole line", "whole", "line"}}
+
+	assignment := &Assignment{Name: "last", Value: NewVarValue("%2")}
+	_, err := assignment.Evaluate(environment)
+	assert.NoError(err)
+
+	// The variable keeps its value when the environment moves to the next
+	// line.
+	environment.Row = &Row{LineNumber: 2, Columns: []string{"next row", "next", "row"}}
+
+	assert.Equal(&AnyValue{"line"}, environment.Resolve(NewVarValue("last").(*VarValue)))
+	assert.Equal(&AnyValue{"row"}, environment.Resolve(NewVarValue("%2").(*VarValue)))
+}
+
+func TestAssignmentOfLiterals(t *testing.T) {
+	assert := assert.New(t)
+
+	environment := NewEnvironment()
+
+	_, err := (&Assignment{Name: "i", Value: NewIntegerValue("12", 12)}).Evaluate(environment)
+	assert.NoError(err)
+	_, err = (&Assignment{Name: "s", Value: NewStringValue(`"abc"`)}).Evaluate(environment)
+	assert.NoError(err)
+
+	assert.Equal(NewIntegerValue("12", 12), environment.Variables["i"])
+	assert.Equal(NewStringValue(`"abc"`), environment.Variables["s"])
+}
+
+func TestAssignmentCount(t *testing.T) {
+	assert := assert.New(t)
+
+	environment := NewEnvironment()
+	environment.Row = NewRow(1, "a 5")
+
+	// A variable that hasn't been assigned is 0 in arithmetic, but a missing
+	// column is still null.
+	count := &Assignment{Name: "count", Value: &Arithmetic{NewVarValue("count"), ADD_Operator, NewIntegerValue("1", 1)}}
+	for i := 0; i < 2; i++ {
+		_, err := count.Evaluate(environment)
+		assert.NoError(err)
+	}
+	missing := &Assignment{Name: "missing", Value: &Arithmetic{NewVarValue("%3"), ADD_Operator, NewIntegerValue("1", 1)}}
+	_, err := missing.Evaluate(environment)
+	assert.NoError(err)
+
+	assert.Equal(&IntegerValue{"2", 2}, environment.Variables["count"])
+	assert.Equal(NewNullValue(), environment.Variables["missing"])
+}
+
+func TestColumnAssignment(t *testing.T) {
+	assert := assert.New(t)
+
+	environment := NewEnvironment()
+	environment.Row = NewRow(1, "a  3  x")
+
+	statements := []Expression{
+		&Assignment{Name: "%2", Value: &Arithmetic{NewVarValue("%2"), ADD_Operator, NewIntegerValue("10", 10)}},
+		&Assignment{Name: "%-1", Value: NewNullValue()},
+		&AppendColumn{NewStringValue(`"end"`)},
+		&DeleteColumn{"%1"},
+	}
+	for _, s := range statements {
+		_, err := s.Evaluate(environment)
+		assert.NoError(err)
+	}
+
+	assert.Equal(&AnyValue{"13   end"}, environment.Resolve(NewVarValue("%0").(*VarValue)))
+	assert.Equal(&AnyValue{"13"}, environment.Resolve(NewVarValue("%1").(*VarValue)))
+
+	_, err := (&Assignment{Name: "%1", Value: NewIntegerValue("1", 1)}).Evaluate(NewEnvironment())
+	assert.Error(err)
+}
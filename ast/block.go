@@ -4,37 +4,47 @@ import (
 	"fmt"
 )
 
-// Block represents a block of commands in a program.
+// Block represents a block of statements in a program. A block is an
+// expression, its value is the value of the last statement, or null if the
+// block is empty.
 type Block struct {
-	Commands []*Command
+	Statements []Expression
 }
 
-// NewPrintlnBlock is a convenience method for a block with a single println
-// statement that prints the complete line.
+// NewPrintlnBlock is a convenience method for the block a rule without a
+// block gets, `{ %0 }`, which prints the complete line.
 func NewPrintlnBlock() *Block {
 	return &Block{
-		Commands: []*Command{
-			&Command{
-				Name:       "println",
-				Parameters: []Expression{NewVarValue("%0")},
-			},
-		},
+		Statements: []Expression{NewVarValue("%0")},
 	}
 }
 
-func (b *Block) Execute(environment *Environment) error {
-	for _, command := range b.Commands {
-		if err := command.Execute(environment); err != nil {
-			return err
+func (b *Block) Evaluate(environment *Environment) (interface{}, error) {
+	var v interface{}
+	for _, statement := range b.Statements {
+		var err error
+		if v, err = statement.Evaluate(environment); err != nil {
+			return nil, err
 		}
 	}
-	return nil
+	return v, nil
 }
 
-func (b *Block) LastCommand() *Command {
-	return b.Commands[len(b.Commands)-1]
+// Execute runs the block for a line, and prints the value of the block,
+// unless it is null, so `{ %1 }` is the same as `{ println(%1) }`. The print
+// commands, and assignments, don't have a value, so a block that ends with one
+// of them doesn't print anything more.
+func (b *Block) Execute(environment *Environment) error {
+	v, err := b.Evaluate(environment)
+	if err != nil {
+		return err
+	}
+	if v != nil && !isNull(v) {
+		fmt.Println(v)
+	}
+	return nil
 }
 
 func (b *Block) String() string {
-	return fmt.Sprintf("Block[%+v]", b.Commands)
+	return fmt.Sprintf("Block[%+v]", b.Statements)
 }
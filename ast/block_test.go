@@ -0,0 +1,41 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockValue(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"a 1", "a", "1"}},
+	}
+
+	tests := []struct {
+		name  string
+		block *Block
+		want  interface{}
+	}{
+		{"empty", &Block{}, nil},
+		{"default", NewPrintlnBlock(), &AnyValue{"a 1"}},
+		{"last statement", &Block{Statements: []Expression{
+			&Assignment{Name: "x", Value: NewVarValue("%2")},
+			NewVarValue("x"),
+		}}, &AnyValue{"1"}},
+		{"assignment", &Block{Statements: []Expression{
+			&Assignment{Name: "x", Value: NewVarValue("%2")},
+		}}, nil},
+		{"missing column", &Block{Statements: []Expression{NewVarValue("%3")}}, NewNullValue()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.block.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
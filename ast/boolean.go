@@ -0,0 +1,63 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/jacobsimpson/jt/datetime"
+)
+
+// isTrue reports whether an expression selects a line, or is a true
+// condition of an if or a loop. Any expression can be used as a selection, and
+// its value is judged by the truthiness rules of isTruthy. An expression that
+// can't be evaluated, like `int(%2)` for a line where %2 isn't an integer, is
+// false, in the same way a comparison that can't be made is false. A next or
+// an exit in the expression still happens, so it is returned as the error.
+func isTrue(environment *Environment, expression Expression) (bool, error) {
+	v, err := evaluateValue(environment, expression)
+	if isControl(err) {
+		return false, err
+	} else if err != nil {
+		return false, nil
+	}
+	return isTruthy(v), nil
+}
+
+// isTruthy reports whether a value counts as true.
+//
+//	null, false, an empty string, 0, 0.0, a zero duration and an empty list,
+//	map or set are false.
+//	A value from the input is false if it is empty, "false" or a number that
+//	is 0.
+//	Everything else is true.
+func isTruthy(v Value) bool {
+	switch t := v.(type) {
+	case nil, *NullValue:
+		return false
+	case *BooleanValue:
+		return t.value
+	case *AnyValue:
+		s := strings.TrimSpace(t.raw)
+		if s == "" {
+			return false
+		}
+		if b, err := parseBool(s); err == nil {
+			return b
+		}
+		if d, err := parseDecimal(s); err == nil {
+			return !d.IsZero()
+		}
+		return true
+	case *StringValue:
+		return t.value != ""
+	case *IntegerValue:
+		return t.value != 0
+	case *DoubleValue:
+		return !t.value.IsZero()
+	case *DurationValue:
+		return t.value != datetime.Duration{}
+	case *ListValue, *MapValue, *SetValue:
+		values, _ := elements(t)
+		return len(values) > 0
+	}
+	return true
+}
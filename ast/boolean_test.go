@@ -0,0 +1,75 @@
+package ast
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTruthy(t *testing.T) {
+	tests := []struct {
+		value Value
+		want  bool
+	}{
+		{NewBooleanValue(true), true},
+		{NewBooleanValue(false), false},
+		{&AnyValue{""}, false},
+		{&AnyValue{" "}, false},
+		{&AnyValue{"0"}, false},
+		{&AnyValue{"0.0"}, false},
+		{&AnyValue{"false"}, false},
+		{&AnyValue{"FALSE"}, false},
+		{&AnyValue{"abc"}, true},
+		{&AnyValue{"12"}, true},
+		{NewStringValue(`""`), false},
+		{NewStringValue(`"0"`), true},
+		{&IntegerValue{"0", 0}, false},
+		{&IntegerValue{"-1", -1}, true},
+		{mustDouble(t, "0.0"), false},
+		{mustDouble(t, "0.5"), true},
+		{mustDuration(t, "0s"), false},
+		{mustDuration(t, "1D"), true},
+		{mustDateTime(t, "2013T"), true},
+		{NewListValue([]Value{}), false},
+		{NewListValue([]Value{&AnyValue{""}}), true},
+		{nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v", test.value), func(t *testing.T) {
+			assert.Equal(t, test.want, isTruthy(test.value))
+		})
+	}
+}
+
+func TestIsTrue(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"a 0 b", "a", "0", "b"}},
+	}
+
+	one := 1
+	tests := []struct {
+		expression Expression
+		want       bool
+	}{
+		{NewVarValue("%1"), true},
+		{NewVarValue("%2"), false},
+		{NewVarValue("%4"), false},
+		{&RangeExpression{Expression: NewVarValue("%1"), Start: &one}, false},
+		{&Comparison{NewVarValue("%2"), EQ_Operator, NewBooleanValue(false)}, false},
+		{&Command{Name: "int", Parameters: []Expression{NewVarValue("%1")}}, false},
+		{&Command{Name: "int", Parameters: []Expression{NewVarValue("%2")}}, false},
+		{NewNegativeExpression(&Command{Name: "int", Parameters: []Expression{NewVarValue("%1")}}), true},
+		{&failingExpression{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression.String(), func(t *testing.T) {
+			got, err := isTrue(environment, test.expression)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package ast
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jacobsimpson/jt/datetime"
+)
+
+// Builtin is a function that is built in to the language. The parameters are
+// evaluated before the function is called. A builtin can be called as
+// `f(x, y)`, or as a method, `x.f(y)`, which is the same call.
+type Builtin func(environment *Environment, parameters []Value) (interface{}, error)
+
+// Builtins are the functions that can be called by name from a program.
+var Builtins = map[string]Builtin{
+	"contains":   contains,
+	"date":       convertDate,
+	"decimal":    convertDecimal,
+	"endswith":   endsWith,
+	"format":     format,
+	"int":        convertInt,
+	"join":       join,
+	"len":        length,
+	"lines":      lines,
+	"lower":      lower,
+	"ltrim":      ltrim,
+	"regex":      convertRegex,
+	"replace":    replace,
+	"reverse":    reverse,
+	"rtrim":      rtrim,
+	"split":      split,
+	"startswith": startsWith,
+	"string":     convertString,
+	"title":      title,
+	"trim":       trim,
+	"upper":      upper,
+}
+
+// format renders a date/time with a strftime style pattern. The date/time can
+// be a value from the input, in any of the formats a date/time can be coerced
+// from.
+//
+//	format(%5, "%Y-%m-%dT%H:%M")
+func format(environment *Environment, parameters []Value) (interface{}, error) {
+	if len(parameters) != 2 {
+		return nil, fmt.Errorf("format takes a date/time and a pattern, got %d parameters", len(parameters))
+	}
+	t, err := toDateTime(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := toText(parameters[1])
+	if err != nil {
+		return nil, err
+	}
+	return toValue(datetime.Format(t, pattern))
+}
+
+// length counts the characters in a string, or the bytes if the environment
+// is counting bytes, or the elements of a collection.
+//
+//	len(%2)
+//	len(%0.split(","))
+func length(environment *Environment, parameters []Value) (interface{}, error) {
+	if len(parameters) != 1 {
+		return nil, fmt.Errorf("len takes a string, got %d parameters", len(parameters))
+	}
+	if values, ok := elements(parameters[0]); ok {
+		return newInteger(int64(len(values))), nil
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	if environment.Bytes {
+		return newInteger(int64(len(s))), nil
+	}
+	return newInteger(int64(utf8.RuneCountInString(s))), nil
+}
+
+// toDateTime coerces a value to a date/time.
+func toDateTime(v Value) (time.Time, error) {
+	switch t := v.(type) {
+	case *DateTimeValue:
+		return t.value, nil
+	case *AnyValue, *StringValue:
+		period, err := parseDateTime(t.String())
+		return period.Start, err
+	}
+	return datetime.Zero, fmt.Errorf("%s is not a date/time", v)
+}
+
+// parameterCount checks that a builtin was called with between min and max
+// parameters.
+func parameterCount(parameters []Value, min, max int) error {
+	if len(parameters) < min || len(parameters) > max {
+		if min == max {
+			return fmt.Errorf("takes %d parameters, got %d", min, len(parameters))
+		}
+		return fmt.Errorf("takes %d to %d parameters, got %d", min, max, len(parameters))
+	}
+	return nil
+}
+
+// toText returns the text of a string, or of a value from the input.
+func toText(v Value) (string, error) {
+	switch t := v.(type) {
+	case *AnyValue, *StringValue:
+		return t.String(), nil
+	}
+	return "", fmt.Errorf("%s is not a string", v)
+}
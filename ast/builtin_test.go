@@ -0,0 +1,76 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		date    Value
+		pattern Value
+		want    string
+	}{
+		{mustDateTime(t, "2013-06-01T10:15"), NewStringValue(`"%Y-%m-%dT%H:%M"`), "2013-06-01T10:15"},
+		{&AnyValue{"Jan 2, 2006"} /*      */, NewStringValue(`"%a %d/%m/%Y"`) /*  */, "Mon 02/01/2006"},
+		{&AnyValue{"13Jan14"} /*          */, &AnyValue{"%F"} /*                  */, "2014-01-13"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.date.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := format(NewEnvironment(), []Value{test.date, test.pattern})
+
+			assert.NoError(err)
+			assert.Equal(&StringValue{raw: `"` + test.want + `"`, value: test.want}, got)
+		})
+	}
+}
+
+func TestFormatErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters []Value
+	}{
+		{"not a date", []Value{&AnyValue{"abc"}, NewStringValue(`"%F"`)}},
+		{"not a pattern", []Value{&AnyValue{"2006-01-02"}, &IntegerValue{"1", 1}}},
+		{"missing pattern", []Value{&AnyValue{"2006-01-02"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := format(NewEnvironment(), test.parameters)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLength(t *testing.T) {
+	tests := []struct {
+		s     Value
+		bytes bool
+		want  int64
+	}{
+		{&AnyValue{"abc"}, false, 3},
+		{&AnyValue{"日本語"}, false, 3},
+		{&AnyValue{"日本語"}, true, 9},
+		{NewStringValue(`"héllo 👋"`), false, 7},
+		{&AnyValue{""}, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			environment := NewEnvironment()
+			environment.Bytes = test.bytes
+
+			got, err := length(environment, []Value{test.s})
+
+			assert.NoError(err)
+			assert.Equal(newInteger(test.want), got)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// Coalesce is the value of Left, unless Left is null, or can't be evaluated,
+// in which case it is the value of Right. A next or an exit in Left still
+// happens.
+//
+//	%3 ?? "none"
+//	int(%2) ?? 0
+type Coalesce struct {
+	Left  Expression
+	Right Expression
+}
+
+func (c *Coalesce) Evaluate(environment *Environment) (interface{}, error) {
+	v, err := evaluateValue(environment, c.Left)
+	if isControl(err) {
+		return nil, err
+	} else if err == nil && !isNull(v) {
+		return v, nil
+	}
+	return evaluateValue(environment, c.Right)
+}
+
+func (c *Coalesce) String() string {
+	return fmt.Sprintf("(%s ?? %s)", c.Left, c.Right)
+}
@@ -0,0 +1,82 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesce(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"a x", "a", "x"}},
+	}
+	none := NewStringValue(`"none"`)
+
+	tests := []struct {
+		expression Expression
+		want       interface{}
+	}{
+		{&Coalesce{NewVarValue("%1"), none}, &AnyValue{"a"}},
+		{&Coalesce{NewVarValue("%3"), none}, none},
+		{&Coalesce{NewNullValue(), NewNullValue()}, NewNullValue()},
+		{&Coalesce{&Command{Name: "int", Parameters: []Expression{NewVarValue("%2")}}, &IntegerValue{"0", 0}}, &IntegerValue{"0", 0}},
+		{&Coalesce{&Command{Name: "upper", Parameters: []Expression{NewVarValue("%3")}}, none}, none},
+		{&Coalesce{&Arithmetic{NewVarValue("%3"), ADD_Operator, &IntegerValue{"1", 1}}, none}, none},
+		{&Coalesce{&Comparison{NewVarValue("%3"), EQ_Operator, NewNullValue()}, none}, NewBooleanValue(true)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.expression.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
+
+// Null propagates along a chain of method calls, but a builtin called the plain
+// way is called with the null, and a value it can't handle is an error.
+func TestNullPropagation(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"a", "a"}},
+	}
+	method := func(name string, receiver Expression) Expression {
+		return &Command{Name: name, Parameters: []Expression{receiver}, Method: true}
+	}
+	function := func(name string, parameter Expression) Expression {
+		return &Command{Name: name, Parameters: []Expression{parameter}}
+	}
+
+	tests := []Expression{
+		method("upper", NewVarValue("%9")),
+		method("len", method("trim", NewVarValue("%9"))),
+	}
+
+	for _, test := range tests {
+		t.Run(test.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(NewNullValue(), got)
+		})
+	}
+
+	errors := []Expression{
+		function("string", NewVarValue("%9")),
+		function("len", NewVarValue("%9")),
+		function("upper", method("trim", NewVarValue("%9"))),
+	}
+
+	for _, test := range errors {
+		t.Run(test.String(), func(t *testing.T) {
+			_, err := test.Evaluate(environment)
+
+			assert.Error(t, err)
+		})
+	}
+}
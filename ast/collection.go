@@ -0,0 +1,512 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jacobsimpson/jt/datetime"
+)
+
+// MapValue is a Value implementation to hold a set of keys, each with a
+// value. The keys are kept in the order they were added.
+//
+//	{"one": 1, "two": 2}
+type MapValue struct {
+	keys   *valueIndex
+	values []Value
+}
+
+func NewMapValue(keys, values []Value) Value {
+	m := &MapValue{keys: newValueIndex(nil)}
+	for i, k := range keys {
+		m.put(k, values[i])
+	}
+	return m
+}
+
+// put sets the value of a key, replacing the value the key already had.
+func (v *MapValue) put(key, value Value) {
+	if i, added := v.keys.add(key); added {
+		v.values = append(v.values, value)
+	} else {
+		v.values[i] = value
+	}
+}
+
+// get returns the value of a key, or null if the map doesn't have the key.
+func (v *MapValue) get(key Value) Value {
+	if i := v.keys.find(key); i >= 0 {
+		return v.values[i]
+	}
+	return NewNullValue()
+}
+
+func (v *MapValue) Raw() string {
+	return v.String()
+}
+
+func (v *MapValue) Value() interface{} {
+	m := map[Value]Value{}
+	for i, k := range v.keys.values {
+		m[k] = v.values[i]
+	}
+	return m
+}
+
+func (v *MapValue) String() string {
+	if len(v.values) == 0 {
+		return "{:}"
+	}
+	entries := []string{}
+	for i, k := range v.keys.values {
+		entries = append(entries, k.String()+": "+v.values[i].String())
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+func (v *MapValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v, nil
+}
+
+// SetValue is a Value implementation to hold values without duplicates. The
+// values are kept in the order they were added.
+//
+//	{'a', 'b'}
+type SetValue struct {
+	index *valueIndex
+}
+
+func NewSetValue(values []Value) Value {
+	return &SetValue{newValueIndex(values)}
+}
+
+func (v *SetValue) Raw() string {
+	return v.String()
+}
+
+func (v *SetValue) Value() interface{} {
+	return v.index.values
+}
+
+func (v *SetValue) String() string {
+	values := []string{}
+	for _, e := range v.index.values {
+		values = append(values, e.String())
+	}
+	return "{" + strings.Join(values, ", ") + "}"
+}
+
+func (v *SetValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v, nil
+}
+
+// TupleValue is a Value implementation to hold a fixed number of values that
+// belong together, like the columns of a line.
+//
+//	<"abc", 1>
+type TupleValue struct {
+	values []Value
+}
+
+func NewTupleValue(values []Value) Value {
+	return &TupleValue{values}
+}
+
+func (v *TupleValue) Raw() string {
+	return v.String()
+}
+
+func (v *TupleValue) Value() interface{} {
+	return v.values
+}
+
+func (v *TupleValue) String() string {
+	values := []string{}
+	for _, e := range v.values {
+		values = append(values, e.String())
+	}
+	return "<" + strings.Join(values, ", ") + ">"
+}
+
+func (v *TupleValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v, nil
+}
+
+// elements returns the values of a collection, in order, and whether the value
+// is a collection. The elements of a map are its keys.
+func elements(v Value) ([]Value, bool) {
+	switch t := v.(type) {
+	case *ListValue:
+		return t.values, true
+	case *TupleValue:
+		return t.values, true
+	case *SetValue:
+		return t.index.values, true
+	case *MapValue:
+		return t.keys.values, true
+	}
+	return nil, false
+}
+
+// isIn reports whether a collection has an element equal to the value, or
+// whether the value is within a range. Lists and tuples are searched in
+// order, sets and maps are hashed.
+func isIn(environment *Environment, left, right Expression) bool {
+	value, ok := resolveVar(environment, left).(Value)
+	if !ok {
+		return false
+	}
+	switch c := resolveVar(environment, right).(type) {
+	case *ListValue:
+		return indexOf(c.values, value) >= 0
+	case *TupleValue:
+		return indexOf(c.values, value) >= 0
+	case *SetValue:
+		return c.index.find(value) >= 0
+	case *MapValue:
+		return c.keys.find(value) >= 0
+	case *IntervalValue:
+		return c.contains(value)
+	}
+	return false
+}
+
+// indexOf returns the position of the first of the values that is equal to
+// v, or -1.
+func indexOf(values []Value, v Value) int {
+	for i, e := range values {
+		if eq(nil, v, e) {
+			return i
+		}
+	}
+	return -1
+}
+
+// valueIndex finds values without comparing against every one of them. Two
+// values that are equal, by the rules of eq, always share at least one hash
+// key, so only the values that share a key with a value have to be compared
+// with it. A value that has no hash keys, like a regular expression, might be
+// equal to anything, so it is compared with every value that is searched for.
+type valueIndex struct {
+	values   []Value
+	buckets  map[string][]int
+	unhashed []int
+}
+
+func newValueIndex(values []Value) *valueIndex {
+	index := &valueIndex{buckets: map[string][]int{}}
+	for _, v := range values {
+		index.add(v)
+	}
+	return index
+}
+
+// add adds a value that isn't already in the index. It returns the position
+// of the value, and whether it was added.
+func (x *valueIndex) add(v Value) (int, bool) {
+	if i := x.find(v); i >= 0 {
+		return i, false
+	}
+	i := len(x.values)
+	x.values = append(x.values, v)
+	keys := hashKeys(v)
+	if keys == nil {
+		x.unhashed = append(x.unhashed, i)
+	}
+	for _, k := range keys {
+		x.buckets[k] = append(x.buckets[k], i)
+	}
+	return i, true
+}
+
+// find returns the position of the first value in the index that is equal to
+// v, or -1.
+func (x *valueIndex) find(v Value) int {
+	keys := hashKeys(v)
+	if keys == nil {
+		return indexOf(x.values, v)
+	}
+	found := -1
+	check := func(i int) {
+		if (found < 0 || i < found) && eq(nil, v, x.values[i]) {
+			found = i
+		}
+	}
+	for _, k := range keys {
+		for _, i := range x.buckets[k] {
+			check(i)
+		}
+	}
+	for _, i := range x.unhashed {
+		check(i)
+	}
+	return found
+}
+
+// hashKeys returns the keys a value is hashed under. A value from the input
+// can be equal to a string, a number, a boolean or a duration, so it has a key
+// for each of them that it can be coerced to. Values that are compared by
+// more than their text, like dates, which are periods, and regular
+// expressions, have no keys.
+func hashKeys(v Value) []string {
+	switch t := v.(type) {
+	case *NullValue:
+		return []string{"null"}
+	case *StringValue:
+		return []string{"s" + t.value}
+	case *BooleanValue:
+		return []string{fmt.Sprintf("b%t", t.value)}
+	case *IntegerValue, *DoubleValue:
+		return []string{"n" + toDecimal(t).String()}
+	case *DurationValue:
+		return []string{fmt.Sprintf("u%d", t.value.Approximate())}
+	case *AnyValue:
+		keys := []string{"s" + t.raw}
+		if t.raw == "" {
+			return keys
+		}
+		// Only text that starts like a number, a boolean or a duration is
+		// parsed, since most of the values searched for are neither, and a
+		// parse that fails is slow.
+		switch c := t.raw[0]; {
+		case c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.':
+			if d, err := parseDecimal(t.raw); err == nil {
+				keys = append(keys, "n"+d.String())
+			}
+			if d, err := datetime.ParseDuration(t.raw); err == nil {
+				keys = append(keys, fmt.Sprintf("u%d", d.Approximate()))
+			}
+		case len(t.raw) <= len("false"):
+			if b, err := parseBool(t.raw); err == nil {
+				keys = append(keys, fmt.Sprintf("b%t", b))
+			}
+		}
+		return keys
+	case *ListValue:
+		return combinedKeys("l", t.values)
+	case *TupleValue:
+		return combinedKeys("t", t.values)
+	}
+	return nil
+}
+
+// combinedKeys returns the keys of a sequence of values, one for each
+// combination of the keys of its elements. If any element has no keys,
+// neither does the sequence.
+func combinedKeys(prefix string, values []Value) []string {
+	keys := []string{prefix}
+	for _, v := range values {
+		elementKeys := hashKeys(v)
+		if elementKeys == nil {
+			return nil
+		}
+		combined := []string{}
+		for _, k := range keys {
+			for _, e := range elementKeys {
+				combined = append(combined, fmt.Sprintf("%s%d:%s", k, len(e), e))
+			}
+		}
+		keys = combined
+	}
+	return keys
+}
+
+// Index is an element of a collection. Lists and tuples are indexed by
+// position, starting at 0, with negative positions counting back from the
+// end. Maps are indexed by key. An element that doesn't exist is null.
+//
+//	%0.split(",")[-1]
+//	counts["error"]
+type Index struct {
+	Collection Expression
+	Index      Expression
+}
+
+func (e *Index) Evaluate(environment *Environment) (interface{}, error) {
+	c, err := evaluateValue(environment, e.Collection)
+	if err != nil {
+		return nil, err
+	}
+	i, err := evaluateValue(environment, e.Index)
+	if err != nil {
+		return nil, err
+	}
+	switch t := c.(type) {
+	case *NullValue:
+		return t, nil
+	case *MapValue:
+		return t.get(i), nil
+	case *ListValue:
+		return element(t.values, i)
+	case *TupleValue:
+		return element(t.values, i)
+	}
+	return nil, fmt.Errorf("%s can not be indexed", e.Collection)
+}
+
+func (e *Index) String() string {
+	return fmt.Sprintf("%s[%s]", e.Collection, e.Index)
+}
+
+// element returns the value at a position of a list or tuple.
+func element(values []Value, position Value) (interface{}, error) {
+	p, err := convertInt(nil, []Value{position})
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a position: %v", position, err)
+	}
+	i := int(p.(*IntegerValue).value)
+	if i < 0 {
+		i += len(values)
+	}
+	if i < 0 || i >= len(values) {
+		return NewNullValue(), nil
+	}
+	return values[i], nil
+}
+
+// ListLiteral, MapLiteral, SetLiteral and TupleLiteral are collections written
+// in a program, with elements that have to be evaluated each time, like
+// `[%1, %2]`. A collection of values that don't change is made once, when the
+// program is parsed.
+type ListLiteral struct {
+	Elements []Expression
+}
+
+func NewListLiteral(elements []Expression) Expression {
+	if values, ok := constant(elements); ok {
+		return NewListValue(values)
+	}
+	return &ListLiteral{elements}
+}
+
+func (e *ListLiteral) Evaluate(environment *Environment) (interface{}, error) {
+	values, err := evaluateAll(environment, e.Elements)
+	if err != nil {
+		return nil, err
+	}
+	return NewListValue(values), nil
+}
+
+func (e *ListLiteral) String() string {
+	return "[" + joinExpressions(e.Elements) + "]"
+}
+
+type MapLiteral struct {
+	Keys   []Expression
+	Values []Expression
+}
+
+func NewMapLiteral(keys, values []Expression) Expression {
+	k, keysOK := constant(keys)
+	v, valuesOK := constant(values)
+	if keysOK && valuesOK {
+		return NewMapValue(k, v)
+	}
+	return &MapLiteral{keys, values}
+}
+
+func (e *MapLiteral) Evaluate(environment *Environment) (interface{}, error) {
+	keys, err := evaluateAll(environment, e.Keys)
+	if err != nil {
+		return nil, err
+	}
+	values, err := evaluateAll(environment, e.Values)
+	if err != nil {
+		return nil, err
+	}
+	return NewMapValue(keys, values), nil
+}
+
+func (e *MapLiteral) String() string {
+	if len(e.Keys) == 0 {
+		return "{:}"
+	}
+	entries := []string{}
+	for i, k := range e.Keys {
+		entries = append(entries, k.String()+": "+e.Values[i].String())
+	}
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+type SetLiteral struct {
+	Elements []Expression
+}
+
+func NewSetLiteral(elements []Expression) Expression {
+	if values, ok := constant(elements); ok {
+		return NewSetValue(values)
+	}
+	return &SetLiteral{elements}
+}
+
+func (e *SetLiteral) Evaluate(environment *Environment) (interface{}, error) {
+	values, err := evaluateAll(environment, e.Elements)
+	if err != nil {
+		return nil, err
+	}
+	return NewSetValue(values), nil
+}
+
+func (e *SetLiteral) String() string {
+	return "{" + joinExpressions(e.Elements) + "}"
+}
+
+type TupleLiteral struct {
+	Elements []Expression
+}
+
+func NewTupleLiteral(elements []Expression) Expression {
+	if values, ok := constant(elements); ok {
+		return NewTupleValue(values)
+	}
+	return &TupleLiteral{elements}
+}
+
+func (e *TupleLiteral) Evaluate(environment *Environment) (interface{}, error) {
+	values, err := evaluateAll(environment, e.Elements)
+	if err != nil {
+		return nil, err
+	}
+	return NewTupleValue(values), nil
+}
+
+func (e *TupleLiteral) String() string {
+	return "<" + joinExpressions(e.Elements) + ">"
+}
+
+// constant returns the values of expressions that are all values that don't
+// change from one line to the next.
+func constant(expressions []Expression) ([]Value, bool) {
+	values := []Value{}
+	for _, e := range expressions {
+		switch v := e.(type) {
+		case *VarValue, *KeywordValue:
+			return nil, false
+		case Value:
+			values = append(values, v)
+		default:
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+func evaluateAll(environment *Environment, expressions []Expression) ([]Value, error) {
+	values := []Value{}
+	for _, e := range expressions {
+		v, err := evaluateValue(environment, e)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate %s: %w", e, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func joinExpressions(expressions []Expression) string {
+	s := []string{}
+	for _, e := range expressions {
+		s = append(s, e.String())
+	}
+	return strings.Join(s, ", ")
+}
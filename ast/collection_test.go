@@ -0,0 +1,158 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIn(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"b 3 1h 3.0", "b", "3", "1h", "3.0"}},
+	}
+	integer := func(i int64) Value { return newInteger(i) }
+	letters := []Value{NewStringValue(`"a"`), NewStringValue(`"b"`)}
+	numbers := []Value{integer(1), integer(3)}
+	regexp := func(s string) Value { r, _ := NewRegexpValue(s); return r }
+
+	tests := []struct {
+		name       string
+		value      Expression
+		collection Expression
+		want       bool
+	}{
+		{"list", NewVarValue("%1"), NewListValue(letters), true},
+		{"list missing", NewVarValue("%2"), NewListValue(letters), false},
+		{"list regex", NewVarValue("%1"), NewListValue([]Value{regexp("[a-c]")}), true},
+		{"set", NewVarValue("%1"), NewSetValue(letters), true},
+		{"set number", NewVarValue("%2"), NewSetValue(numbers), true},
+		{"set decimal", NewVarValue("%4"), NewSetValue([]Value{mustDouble(t, "3")}), true},
+		{"set decimal literal", mustDouble(t, "3.00"), NewSetValue(numbers), true},
+		{"set duration", NewVarValue("%3"), NewSetValue([]Value{mustDuration(t, "60m")}), true},
+		{"set regex", NewVarValue("%1"), NewSetValue([]Value{regexp("^b$")}), true},
+		{"set missing", NewVarValue("%2"), NewSetValue(letters), false},
+		{"set null", NewVarValue("%9"), NewSetValue([]Value{NewNullValue()}), true},
+		{"map key", NewVarValue("%1"), NewMapValue(letters, numbers), true},
+		{"map value", NewVarValue("%2"), NewMapValue(letters, numbers), false},
+		{"tuple", NewTupleValue([]Value{&AnyValue{"b"}, &AnyValue{"3"}}),
+			NewSetValue([]Value{NewTupleValue([]Value{NewStringValue(`"b"`), integer(3)})}), true},
+		{"range", NewVarValue("%2"), NewIntervalValue(integer(1), integer(3)), true},
+		{"range below", NewVarValue("%2"), NewIntervalValue(integer(4), integer(9)), false},
+		{"range decimal", NewVarValue("%4"), NewIntervalValue(mustDouble(t, "2.5"), mustDouble(t, "3.5")), true},
+		{"range string", NewVarValue("%1"), NewIntervalValue(NewStringValue(`"a"`), NewStringValue(`"c"`)), true},
+		{"range duration", NewVarValue("%3"), NewIntervalValue(mustDuration(t, "30m"), mustDuration(t, "2h")), true},
+		{"range date", &AnyValue{"2024-01-31T23:59"}, NewIntervalValue(mustDateTime(t, "2024-01-01T"), mustDateTime(t, "2024-01-31T")), true},
+		{"range date after", &AnyValue{"2024-02-01T00:00"}, NewIntervalValue(mustDateTime(t, "2024-01-01T"), mustDateTime(t, "2024-01-31T")), false},
+		{"range not comparable", NewVarValue("%1"), NewIntervalValue(integer(1), integer(3)), false},
+		{"not a collection", NewVarValue("%1"), NewStringValue(`"abc"`), false},
+		{"null", NewVarValue("%1"), NewNullValue(), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, isIn(environment, test.value, test.collection))
+		})
+	}
+}
+
+func TestCollectionComparisons(t *testing.T) {
+	integer := func(i int64) Value { return newInteger(i) }
+	list := func(values ...Value) Value { return NewListValue(values) }
+	tuple := func(values ...Value) Value { return NewTupleValue(values) }
+
+	tests := []struct {
+		left     Value
+		operator Operator
+		right    Value
+		want     bool
+	}{
+		{list(&AnyValue{"1"}, &AnyValue{"a"}), EQ_Operator, list(integer(1), NewStringValue(`"a"`)), true},
+		{list(integer(1), integer(2)), EQ_Operator, list(integer(1)), false},
+		{list(integer(1), integer(2)), NE_Operator, list(integer(1), integer(3)), true},
+		{list(integer(1), integer(2)), LT_Operator, list(integer(1), integer(3)), true},
+		{list(integer(1)), LT_Operator, list(integer(1), integer(0)), true},
+		{list(integer(2)), LE_Operator, list(integer(1), integer(9)), false},
+		{tuple(&AnyValue{"b"}, integer(1)), GT_Operator, tuple(NewStringValue(`"a"`), integer(2)), true},
+		{tuple(integer(1), integer(2)), GE_Operator, tuple(integer(1), integer(2)), true},
+		{tuple(integer(1)), EQ_Operator, list(integer(1)), false},
+		{NewSetValue([]Value{integer(1), integer(2)}), EQ_Operator, NewSetValue([]Value{integer(2), integer(1), integer(2)}), true},
+		{NewSetValue([]Value{integer(1), integer(2)}), EQ_Operator, NewSetValue([]Value{integer(1)}), false},
+		{NewMapValue([]Value{integer(1)}, []Value{&AnyValue{"x"}}), EQ_Operator, NewMapValue([]Value{integer(1)}, []Value{NewStringValue(`"x"`)}), true},
+		{NewMapValue([]Value{integer(1)}, []Value{integer(1)}), EQ_Operator, NewMapValue([]Value{integer(1)}, []Value{integer(2)}), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.left.String()+" "+test.operator.String()+" "+test.right.String(), func(t *testing.T) {
+			assert.Equal(t, test.want, Comparisons[test.operator](nil, test.left, test.right))
+		})
+	}
+}
+
+func TestIndex(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"1 -1 x", "1", "-1", "x"}},
+	}
+	integer := func(i int64) Value { return newInteger(i) }
+	list := NewListValue([]Value{NewStringValue(`"a"`), NewStringValue(`"b"`), NewStringValue(`"c"`)})
+
+	tests := []struct {
+		expression Expression
+		want       interface{}
+	}{
+		{&Index{list, integer(0)}, NewStringValue(`"a"`)},
+		{&Index{list, NewVarValue("%1")}, NewStringValue(`"b"`)},
+		{&Index{list, NewVarValue("%2")}, NewStringValue(`"c"`)},
+		{&Index{list, integer(3)}, NewNullValue()},
+		{&Index{NewTupleValue([]Value{integer(5), integer(6)}), integer(-2)}, integer(5)},
+		{&Index{NewMapValue([]Value{integer(1)}, []Value{NewStringValue(`"one"`)}), NewVarValue("%1")}, NewStringValue(`"one"`)},
+		{&Index{NewMapValue([]Value{integer(1)}, []Value{NewStringValue(`"one"`)}), integer(2)}, NewNullValue()},
+		{&Index{NewVarValue("%9"), integer(0)}, NewNullValue()},
+		{&Index{NewListLiteral([]Expression{NewVarValue("%3"), NewVarValue("%1")}), integer(1)}, &AnyValue{"1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.expression.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+
+	_, err := (&Index{NewVarValue("%3"), integer(0)}).Evaluate(environment)
+	assert.Error(t, err)
+	_, err = (&Index{list, NewVarValue("%3")}).Evaluate(environment)
+	assert.Error(t, err)
+}
+
+func TestCollectionLiterals(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"a 1", "a", "1"}},
+	}
+	integer := func(i int64) Value { return newInteger(i) }
+
+	tests := []struct {
+		expression Expression
+		want       string
+	}{
+		{NewListLiteral([]Expression{NewVarValue("%1"), integer(2)}), "[a, 2]"},
+		{NewSetLiteral([]Expression{NewVarValue("%2"), integer(1), integer(2)}), "{1, 2}"},
+		{NewMapLiteral([]Expression{NewVarValue("%1"), NewStringValue(`"a"`)}, []Expression{integer(1), integer(2)}), "{a: 2}"},
+		{NewMapLiteral([]Expression{}, []Expression{}), "{:}"},
+		{NewSetLiteral([]Expression{}), "{}"},
+		{NewTupleLiteral([]Expression{NewVarValue("%1"), NewVarValue("%2")}), "<a, 1>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.expression.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got.(Value).String())
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AppendColumn adds a column to the end of the current line.
+//
+//	append %2 * %3
+type AppendColumn struct {
+	Value Expression
+}
+
+func (e *AppendColumn) Evaluate(environment *Environment) (interface{}, error) {
+	v, err := evaluateValue(environment, e.Value)
+	if err != nil {
+		return nil, fmt.Errorf("could not append %s: %w", e.Value, err)
+	}
+	if environment.Row == nil {
+		return nil, fmt.Errorf("there is no line to append %s to", e.Value)
+	}
+	environment.Row.Append(fmt.Sprint(printable(v)))
+	return nil, nil
+}
+
+func (e *AppendColumn) String() string {
+	return fmt.Sprintf("append %s", e.Value)
+}
+
+// DeleteColumn removes a column from the current line. The columns after it
+// move down, so after `delete %2`, the column that was %3 is %2.
+type DeleteColumn struct {
+	Column string
+}
+
+func (e *DeleteColumn) Evaluate(environment *Environment) (interface{}, error) {
+	if environment.Row == nil {
+		return nil, fmt.Errorf("there is no line to delete %s from", e.Column)
+	}
+	i, err := strconv.Atoi(e.Column[1:])
+	if err != nil {
+		return nil, fmt.Errorf("%s can not be deleted", e.Column)
+	}
+	return nil, environment.Row.Delete(i)
+}
+
+func (e *DeleteColumn) String() string {
+	return fmt.Sprintf("delete %s", e.Column)
+}
@@ -5,35 +5,99 @@ import (
 	"strings"
 )
 
+// Command is a call to a named function, with a list of parameters. The
+// parameters can be any expression, including other commands. A method call,
+// like `%1.trim()`, is a command with the receiver as its first parameter.
 type Command struct {
 	Name       string
 	Parameters []Expression
+	Method     bool
 }
 
-func (c *Command) Execute(environment *Environment) error {
+// Evaluate runs the command as part of a larger expression. The print
+// commands don't produce a value, so they evaluate to nil. Any other command
+// is a call to a function the program defines, or to one of the Builtins. A
+// builtin method called on null, like `%9.trim()`, isn't called, and is null
+// itself, so null propagates along a chain of calls. A function the program
+// defines, and a builtin called as a function, like `string(%9)`, are always
+// called, so they can handle null themselves.
+func (c *Command) Evaluate(environment *Environment) (interface{}, error) {
 	switch c.Name {
 	case "println", "print":
-		formats := []string{}
-		values := []interface{}{}
-		for _, p := range c.Parameters {
-			formats = append(formats, "%s")
-			v, err := p.Evaluate(environment)
-			if err != nil {
-				return fmt.Errorf("could not evaluate parameter %s: %v", p, err)
-			}
-			values = append(values, v)
+		return nil, c.print(environment)
+	}
+
+	builtin, ok := Builtins[c.Name]
+	f, defined := environment.Functions[c.Name]
+	if defined {
+		builtin, ok = f.Call, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q: 1:11", c.Name)
+	}
+	parameters := []Value{}
+	for _, p := range c.Parameters {
+		v, err := evaluateValue(environment, p)
+		if _, ok := err.(*callDepthError); ok {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("could not evaluate parameter %s: %w", p, err)
 		}
-		format := strings.Join(formats, " ")
-		if c.Name == "println" {
-			format = format + "\n"
+		if c.Method && !defined && len(parameters) == 0 && isNull(v) {
+			return v, nil
+		}
+		parameters = append(parameters, v)
+	}
+	v, err := builtin(environment, parameters)
+	if _, ok := err.(*callDepthError); ok {
+		return nil, err
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Name, err)
+	}
+	return v, nil
+}
+
+// Execute runs the command as a statement, discarding any value it produces.
+func (c *Command) Execute(environment *Environment) error {
+	_, err := c.Evaluate(environment)
+	return err
+}
+
+func (c *Command) print(environment *Environment) error {
+	formats := []string{}
+	values := []interface{}{}
+	for _, p := range c.Parameters {
+		formats = append(formats, "%v")
+		v, err := p.Evaluate(environment)
+		if err != nil {
+			return fmt.Errorf("could not evaluate parameter %s: %w", p, err)
 		}
-		fmt.Printf(format, values...)
-	default:
-		return fmt.Errorf("unknown function %q: 1:11", c.Name)
+		values = append(values, printable(v))
 	}
+	format := strings.Join(formats, " ")
+	if c.Name == "println" {
+		format = format + "\n"
+	}
+	fmt.Printf(format, values...)
 	return nil
 }
 
+// printable turns null into an empty string, so that null prints as nothing.
+func printable(v interface{}) interface{} {
+	if isNull(v) {
+		return ""
+	}
+	return v
+}
+
+func (c *Command) String() string {
+	parameters := []string{}
+	for _, p := range c.Parameters {
+		parameters = append(parameters, fmt.Sprintf("%v", p))
+	}
+	return fmt.Sprintf("%s(%s)", c.Name, strings.Join(parameters, ", "))
+}
+
 func (c *Command) AddParameter(parameter Expression) {
 	c.Parameters = append(c.Parameters, parameter)
 }
@@ -17,42 +17,78 @@ var Comparisons = map[Operator]func(*Environment, Expression, Expression) bool{
 	NE_Operator: ne,
 	GE_Operator: ge,
 	GT_Operator: gt,
+	IN_Operator: isIn,
 }
 
 func (c *Comparison) Evaluate(environment *Environment) (interface{}, error) {
-	return Comparisons[c.Operator](environment, c.Left, c.Right), nil
+	left, err := comparisonOperand(environment, c.Left)
+	if err != nil {
+		return false, err
+	}
+	right, err := comparisonOperand(environment, c.Right)
+	if err != nil {
+		return false, err
+	}
+	if left == nil || right == nil {
+		return false, nil
+	}
+	return Comparisons[c.Operator](environment, left, right), nil
 }
 
 func (c *Comparison) String() string {
 	return fmt.Sprintf("%s %s %s", c.Left, c.Operator, c.Right)
 }
 
+// comparisonOperand evaluates an operand of a comparison that is more
+// complicated than a value, so that a next or an exit in it still happens. An
+// operand that can't be evaluated is nil, and doesn't compare to anything. It
+// isn't given to the comparison functions, which would evaluate it again.
+func comparisonOperand(environment *Environment, expression Expression) (Expression, error) {
+	switch expression.(type) {
+	case Value, *VarValue, *KeywordValue:
+		return expression, nil
+	}
+	v, err := evaluateValue(environment, expression)
+	if isControl(err) {
+		return nil, err
+	} else if err != nil {
+		return nil, nil
+	}
+	return v, nil
+}
+
+// AndComparison is true when both of its expressions are true. The right
+// expression is only evaluated if the left expression is true.
 type AndComparison struct {
 	Left  Expression
 	Right Expression
 }
 
 func (c *AndComparison) Evaluate(environment *Environment) (interface{}, error) {
-	l, err := c.Left.Evaluate(environment)
-	if err != nil {
-		return nil, err
-	}
-	r, err := c.Right.Evaluate(environment)
-	if err != nil {
-		return nil, err
-	}
-	lb, ok := l.(bool)
-	if !ok {
-		return false, nil
-	}
-	rb, ok := r.(bool)
-	if !ok {
-		return false, nil
+	if ok, err := isTrue(environment, c.Left); !ok {
+		return false, err
 	}
-
-	return lb && rb, nil
+	return isTrue(environment, c.Right)
 }
 
 func (c *AndComparison) String() string {
-	return fmt.Sprintf("%s and %s", c.Left, c.Right)
+	return fmt.Sprintf("(%s and %s)", c.Left, c.Right)
+}
+
+// OrComparison is true when either of its expressions is true. The right
+// expression is only evaluated if the left expression is not true.
+type OrComparison struct {
+	Left  Expression
+	Right Expression
+}
+
+func (c *OrComparison) Evaluate(environment *Environment) (interface{}, error) {
+	if ok, err := isTrue(environment, c.Left); ok || err != nil {
+		return ok, err
+	}
+	return isTrue(environment, c.Right)
+}
+
+func (c *OrComparison) String() string {
+	return fmt.Sprintf("(%s or %s)", c.Left, c.Right)
 }
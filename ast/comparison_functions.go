@@ -1,10 +1,6 @@
 package ast
 
 import (
-	"strconv"
-	"strings"
-	"time"
-
 	"github.com/jacobsimpson/jt/datetime"
 	"github.com/shopspring/decimal"
 )
@@ -20,6 +16,8 @@ func lt(environment *Environment, left, right Expression) bool {
 			return anyGTAny(r, l)
 		case *DateTimeValue:
 			return dateTimeGTAny(r, l)
+		case *DurationValue:
+			return durationGTAny(r, l)
 		case *DoubleValue:
 			return doubleGTAny(r, l)
 		case *IntegerValue:
@@ -34,6 +32,13 @@ func lt(environment *Environment, left, right Expression) bool {
 		case *DateTimeValue:
 			return dateTimeLTDateTime(l, r)
 		}
+	case *DurationValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return durationLTAny(l, r)
+		case *DurationValue:
+			return durationLTDuration(l, r)
+		}
 	case *DoubleValue:
 		switch r := right.(type) {
 		case *AnyValue:
@@ -52,6 +57,14 @@ func lt(environment *Environment, left, right Expression) bool {
 		case *IntegerValue:
 			return integerLTInteger(l, r)
 		}
+	case *ListValue:
+		if r, ok := right.(*ListValue); ok {
+			return sequenceLTSequence(l.values, r.values)
+		}
+	case *TupleValue:
+		if r, ok := right.(*TupleValue); ok {
+			return sequenceLTSequence(l.values, r.values)
+		}
 	case *StringValue:
 		switch r := right.(type) {
 		case *AnyValue:
@@ -74,6 +87,8 @@ func le(environment *Environment, left, right Expression) bool {
 			return anyGTAny(r, l) || anyEQAny(r, l)
 		case *DateTimeValue:
 			return dateTimeGTAny(r, l) || dateTimeEQAny(r, l)
+		case *DurationValue:
+			return durationGTAny(r, l) || durationEQAny(r, l)
 		case *DoubleValue:
 			return doubleGTAny(r, l) || doubleEQAny(r, l)
 		case *IntegerValue:
@@ -85,16 +100,44 @@ func le(environment *Environment, left, right Expression) bool {
 		switch r := right.(type) {
 		case *AnyValue:
 			return dateTimeLTAny(l, r) || dateTimeEQAny(l, r)
+		case *DateTimeValue:
+			return dateTimeLTDateTime(l, r) || dateTimeEQDateTime(l, r)
+		}
+	case *DurationValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return durationLTAny(l, r) || durationEQAny(l, r)
+		case *DurationValue:
+			return !durationGTDuration(l, r)
+		}
+	case *DoubleValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return doubleLTAny(l, r) || doubleEQAny(l, r)
+		case *DoubleValue, *IntegerValue:
+			return !numberGTNumber(l, r)
 		}
 	case *IntegerValue:
 		switch r := right.(type) {
 		case *AnyValue:
 			return integerLTAny(l, r) || integerEQAny(l, r)
+		case *DoubleValue, *IntegerValue:
+			return !numberGTNumber(l, r)
+		}
+	case *ListValue:
+		if r, ok := right.(*ListValue); ok {
+			return sequenceLTSequence(l.values, r.values) || sequenceEQSequence(l.values, r.values)
+		}
+	case *TupleValue:
+		if r, ok := right.(*TupleValue); ok {
+			return sequenceLTSequence(l.values, r.values) || sequenceEQSequence(l.values, r.values)
 		}
 	case *StringValue:
 		switch r := right.(type) {
 		case *AnyValue:
 			return stringLTAny(l, r) || stringEQAny(l, r)
+		case *StringValue:
+			return !stringGTString(l, r)
 		}
 	}
 	return false
@@ -109,8 +152,12 @@ func eq(environment *Environment, left, right Expression) bool {
 		switch r := right.(type) {
 		case *AnyValue:
 			return anyEQAny(r, l)
+		case *BooleanValue:
+			return booleanEQAny(r, l)
 		case *DateTimeValue:
 			return dateTimeEQAny(r, l)
+		case *DurationValue:
+			return durationEQAny(r, l)
 		case *DoubleValue:
 			return doubleEQAny(r, l)
 		case *IntegerValue:
@@ -120,15 +167,43 @@ func eq(environment *Environment, left, right Expression) bool {
 		case *StringValue:
 			return stringEQAny(r, l)
 		}
+	case *BooleanValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return booleanEQAny(l, r)
+		case *BooleanValue:
+			return l.value == r.value
+		}
+	case *NullValue:
+		// Null is only equal to null.
+		return isNull(right)
 	case *DateTimeValue:
 		switch r := right.(type) {
 		case *AnyValue:
 			return dateTimeEQAny(l, r)
+		case *DateTimeValue:
+			return dateTimeEQDateTime(l, r)
+		}
+	case *DurationValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return durationEQAny(l, r)
+		case *DurationValue:
+			return durationEQDuration(l, r)
+		}
+	case *DoubleValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return doubleEQAny(l, r)
+		case *DoubleValue, *IntegerValue:
+			return numberEQNumber(l, r)
 		}
 	case *IntegerValue:
 		switch r := right.(type) {
 		case *AnyValue:
 			return integerEQAny(l, r)
+		case *DoubleValue, *IntegerValue:
+			return numberEQNumber(l, r)
 		}
 	case *RegexpValue:
 		switch r := right.(type) {
@@ -137,6 +212,22 @@ func eq(environment *Environment, left, right Expression) bool {
 		case *StringValue:
 			return compareStringEQRegexp(r, l)
 		}
+	case *ListValue:
+		if r, ok := right.(*ListValue); ok {
+			return sequenceEQSequence(l.values, r.values)
+		}
+	case *TupleValue:
+		if r, ok := right.(*TupleValue); ok {
+			return sequenceEQSequence(l.values, r.values)
+		}
+	case *MapValue:
+		if r, ok := right.(*MapValue); ok {
+			return mapEQMap(l, r)
+		}
+	case *SetValue:
+		if r, ok := right.(*SetValue); ok {
+			return setEQSet(l, r)
+		}
 	case *StringValue:
 		switch r := right.(type) {
 		case *AnyValue:
@@ -165,6 +256,8 @@ func ge(environment *Environment, left, right Expression) bool {
 			return anyLTAny(r, l) || anyEQAny(r, l)
 		case *DateTimeValue:
 			return dateTimeLTAny(r, l) || dateTimeEQAny(r, l)
+		case *DurationValue:
+			return durationLTAny(r, l) || durationEQAny(r, l)
 		case *DoubleValue:
 			return doubleLTAny(r, l) || doubleEQAny(r, l)
 		case *IntegerValue:
@@ -176,16 +269,44 @@ func ge(environment *Environment, left, right Expression) bool {
 		switch r := right.(type) {
 		case *AnyValue:
 			return dateTimeGTAny(l, r) || dateTimeEQAny(l, r)
+		case *DateTimeValue:
+			return dateTimeGTDateTime(l, r) || dateTimeEQDateTime(l, r)
+		}
+	case *DurationValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return durationGTAny(l, r) || durationEQAny(l, r)
+		case *DurationValue:
+			return !durationLTDuration(l, r)
+		}
+	case *DoubleValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return doubleGTAny(l, r) || doubleEQAny(l, r)
+		case *DoubleValue, *IntegerValue:
+			return !numberLTNumber(l, r)
 		}
 	case *IntegerValue:
 		switch r := right.(type) {
 		case *AnyValue:
 			return integerGTAny(l, r) || integerEQAny(l, r)
+		case *DoubleValue, *IntegerValue:
+			return !numberLTNumber(l, r)
+		}
+	case *ListValue:
+		if r, ok := right.(*ListValue); ok {
+			return sequenceGTSequence(l.values, r.values) || sequenceEQSequence(l.values, r.values)
+		}
+	case *TupleValue:
+		if r, ok := right.(*TupleValue); ok {
+			return sequenceGTSequence(l.values, r.values) || sequenceEQSequence(l.values, r.values)
 		}
 	case *StringValue:
 		switch r := right.(type) {
 		case *AnyValue:
 			return stringGTAny(l, r) || stringEQAny(l, r)
+		case *StringValue:
+			return !stringLTString(l, r)
 		}
 	}
 	return false
@@ -202,6 +323,8 @@ func gt(environment *Environment, left, right Expression) bool {
 			return anyGTAny(l, r)
 		case *DateTimeValue:
 			return dateTimeLTAny(r, l)
+		case *DurationValue:
+			return durationLTAny(r, l)
 		case *DoubleValue:
 			return doubleLTAny(r, l)
 		case *IntegerValue:
@@ -216,6 +339,13 @@ func gt(environment *Environment, left, right Expression) bool {
 		case *DateTimeValue:
 			return dateTimeGTDateTime(l, r)
 		}
+	case *DurationValue:
+		switch r := right.(type) {
+		case *AnyValue:
+			return durationGTAny(l, r)
+		case *DurationValue:
+			return durationGTDuration(l, r)
+		}
 	case *DoubleValue:
 		switch r := right.(type) {
 		case *AnyValue:
@@ -234,6 +364,14 @@ func gt(environment *Environment, left, right Expression) bool {
 		case *IntegerValue:
 			return integerGTInteger(l, r)
 		}
+	case *ListValue:
+		if r, ok := right.(*ListValue); ok {
+			return sequenceGTSequence(l.values, r.values)
+		}
+	case *TupleValue:
+		if r, ok := right.(*TupleValue); ok {
+			return sequenceGTSequence(l.values, r.values)
+		}
 	case *StringValue:
 		switch r := right.(type) {
 		case *AnyValue:
@@ -245,10 +383,78 @@ func gt(environment *Environment, left, right Expression) bool {
 	return false
 }
 
+// Lists and tuples are compared element by element, by the same rules as the
+// elements themselves, so [%1, %2] == [1, "a"] coerces the columns. The first
+// elements that aren't equal decide the order, and a sequence that runs out
+// first is less than the other.
+func sequenceEQSequence(lhs, rhs []Value) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	for i := range lhs {
+		if !eq(nil, lhs[i], rhs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sequenceLTSequence(lhs, rhs []Value) bool {
+	for i := 0; i < len(lhs) && i < len(rhs); i++ {
+		if !eq(nil, lhs[i], rhs[i]) {
+			return lt(nil, lhs[i], rhs[i])
+		}
+	}
+	return len(lhs) < len(rhs)
+}
+
+func sequenceGTSequence(lhs, rhs []Value) bool {
+	for i := 0; i < len(lhs) && i < len(rhs); i++ {
+		if !eq(nil, lhs[i], rhs[i]) {
+			return gt(nil, lhs[i], rhs[i])
+		}
+	}
+	return len(lhs) > len(rhs)
+}
+
+// Sets are equal when they have the same number of values, and every value of
+// one is in the other. Maps also need the same value for each key.
+func setEQSet(lhs, rhs *SetValue) bool {
+	if len(lhs.index.values) != len(rhs.index.values) {
+		return false
+	}
+	for _, v := range lhs.index.values {
+		if rhs.index.find(v) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func mapEQMap(lhs, rhs *MapValue) bool {
+	if len(lhs.values) != len(rhs.values) {
+		return false
+	}
+	for i, k := range lhs.keys.values {
+		j := rhs.keys.find(k)
+		if j < 0 || !eq(nil, lhs.values[i], rhs.values[j]) {
+			return false
+		}
+	}
+	return true
+}
+
 func compareStringEQRegexp(lhs *StringValue, rhs *RegexpValue) bool {
 	return rhs.re.MatchString(lhs.value)
 }
 
+// A value from the input is equal to a boolean if it is true or false, in any
+// case.
+func booleanEQAny(lhs *BooleanValue, rhs *AnyValue) bool {
+	b, err := parseBool(rhs.raw)
+	return err == nil && lhs.value == b
+}
+
 func compareStringEQString(lhs *StringValue, rhs *StringValue) bool {
 	return lhs.value == rhs.value
 }
@@ -258,38 +464,82 @@ func regexpEQAny(lhs *RegexpValue, rhs *AnyValue) bool {
 	return rev.MatchString(rhs.raw)
 }
 
+// Date/times are compared as periods, so a literal like 2013T is equal to
+// every date/time in 2013, less than every date/time from 2014 on and greater
+// than every date/time before 2013. A value coerced from the input is an
+// instant.
 func dateTimeEQAny(lhs *DateTimeValue, rhs *AnyValue) bool {
-	dt := lhs.value
-	coerced, err := datetime.ParseDateTime(datetime.CoercionFormats, rhs.raw)
+	coerced, err := parseDateTime(rhs.raw)
 	if err != nil {
 		return false
 	}
-	return dt.Equal(coerced)
+	return lhs.period().Equal(datetime.Period{Start: coerced.Start})
 }
 
 func dateTimeLTAny(lhs *DateTimeValue, rhs *AnyValue) bool {
-	dt := lhs.value
-	coerced, err := datetime.ParseDateTime(datetime.CoercionFormats, rhs.raw)
+	coerced, err := parseDateTime(rhs.raw)
 	if err != nil {
 		return false
 	}
-	return dt.Before(coerced)
+	return lhs.period().Before(datetime.Period{Start: coerced.Start})
+}
+
+func dateTimeGTAny(lhs *DateTimeValue, rhs *AnyValue) bool {
+	coerced, err := parseDateTime(rhs.raw)
+	if err != nil {
+		return false
+	}
+	return lhs.period().After(datetime.Period{Start: coerced.Start})
+}
+
+func dateTimeEQDateTime(lhs *DateTimeValue, rhs *DateTimeValue) bool {
+	return lhs.period().Equal(rhs.period())
 }
 
 func dateTimeLTDateTime(lhs *DateTimeValue, rhs *DateTimeValue) bool {
-	return lhs.value.Before(rhs.value)
+	return lhs.period().Before(rhs.period())
 }
 
-func dateTimeGTAny(lhs *DateTimeValue, rhs *AnyValue) bool {
-	coerced, err := datetime.ParseDateTime(datetime.CoercionFormats, rhs.raw)
+func dateTimeGTDateTime(lhs *DateTimeValue, rhs *DateTimeValue) bool {
+	return lhs.period().After(rhs.period())
+}
+
+func durationEQAny(lhs *DurationValue, rhs *AnyValue) bool {
+	coerced, err := datetime.ParseDuration(rhs.raw)
 	if err != nil {
 		return false
 	}
-	return lhs.value.After(coerced)
+	return lhs.value.Approximate() == coerced.Approximate()
 }
 
-func dateTimeGTDateTime(lhs *DateTimeValue, rhs *DateTimeValue) bool {
-	return lhs.value.After(rhs.value)
+func durationLTAny(lhs *DurationValue, rhs *AnyValue) bool {
+	coerced, err := datetime.ParseDuration(rhs.raw)
+	if err != nil {
+		return false
+	}
+	return lhs.value.Approximate() < coerced.Approximate()
+}
+
+func durationGTAny(lhs *DurationValue, rhs *AnyValue) bool {
+	coerced, err := datetime.ParseDuration(rhs.raw)
+	if err != nil {
+		return false
+	}
+	return lhs.value.Approximate() > coerced.Approximate()
+}
+
+// Durations are compared by their approximate length, so 1M is the same as
+// 30D10h30m, rather than 28, 29, 30 or 31 days.
+func durationEQDuration(lhs *DurationValue, rhs *DurationValue) bool {
+	return lhs.value.Approximate() == rhs.value.Approximate()
+}
+
+func durationLTDuration(lhs *DurationValue, rhs *DurationValue) bool {
+	return lhs.value.Approximate() < rhs.value.Approximate()
+}
+
+func durationGTDuration(lhs *DurationValue, rhs *DurationValue) bool {
+	return lhs.value.Approximate() > rhs.value.Approximate()
 }
 
 func integerEQAny(lhs *IntegerValue, rhs *AnyValue) bool {
@@ -337,7 +587,7 @@ func integerGTInteger(lhs *IntegerValue, rhs *IntegerValue) bool {
 
 func doubleEQAny(lhs *DoubleValue, rhs *AnyValue) bool {
 	d := lhs.value
-	parsed, err := decimal.NewFromString(rhs.raw)
+	parsed, err := parseDecimal(rhs.raw)
 	if err != nil {
 		return false
 	}
@@ -346,26 +596,18 @@ func doubleEQAny(lhs *DoubleValue, rhs *AnyValue) bool {
 
 func doubleLTAny(lhs *DoubleValue, rhs *AnyValue) bool {
 	d := lhs.value
-	parsed, err := decimal.NewFromString(rhs.raw)
+	parsed, err := parseDecimal(rhs.raw)
 	if err != nil {
-		parsedInt, err := parseInt(rhs.raw)
-		if err != nil {
-			return false
-		}
-		parsed = decimal.New(parsedInt, 0)
+		return false
 	}
 	return d.LessThan(parsed)
 }
 
 func doubleGTAny(lhs *DoubleValue, rhs *AnyValue) bool {
 	d := lhs.value
-	parsed, err := decimal.NewFromString(rhs.raw)
+	parsed, err := parseDecimal(rhs.raw)
 	if err != nil {
-		parsedInt, err := parseInt(rhs.raw)
-		if err != nil {
-			return false
-		}
-		parsed = decimal.New(parsedInt, 0)
+		return false
 	}
 	return d.GreaterThan(parsed)
 }
@@ -386,6 +628,20 @@ func doubleLTInteger(lhs *DoubleValue, rhs *IntegerValue) bool {
 	return lhs.value.LessThan(decimal.NewFromInt(rhs.value))
 }
 
+// Integers and decimals that are both the result of a calculation, or both
+// literals, are compared as decimals, so 3 == 3.0.
+func numberEQNumber(lhs, rhs Expression) bool {
+	return toDecimal(lhs.(Value)).Equal(toDecimal(rhs.(Value)))
+}
+
+func numberLTNumber(lhs, rhs Expression) bool {
+	return toDecimal(lhs.(Value)).LessThan(toDecimal(rhs.(Value)))
+}
+
+func numberGTNumber(lhs, rhs Expression) bool {
+	return toDecimal(lhs.(Value)).GreaterThan(toDecimal(rhs.(Value)))
+}
+
 func stringEQAny(lhs *StringValue, rhs *AnyValue) bool {
 	return lhs.value == rhs.raw
 }
@@ -418,60 +674,20 @@ func anyLTAny(lhs *AnyValue, rhs *AnyValue) bool {
 	return lhs.raw < rhs.raw
 }
 
-func parseInt(s string) (int64, error) {
-	s = strings.Map(func(r rune) rune {
-		if r == '_' {
-			return -1
-		}
-		return r
-	}, s)
-	if len(s) > 2 {
-		if strings.HasPrefix(s, "0x") {
-			return strconv.ParseInt(s, 0, 64)
-		}
-	}
-	if len(s) > 2 {
-		if strings.HasPrefix(s, "0b") {
-			return strconv.ParseInt(s[2:], 2, 64)
-		}
-	}
-	return strconv.ParseInt(s, 10, 64)
-}
-
 func resolveVar(environment *Environment, v Expression) Expression {
 	// TODO: This is going to crash hard if the variable doesn't exist.
 	switch vr := v.(type) {
 	case *VarValue:
 		return environment.Resolve(vr)
 	case *KeywordValue:
-		switch vr.value {
-		case "yesterday":
-			t := time.Now().Add(-24 * time.Hour)
-			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
-			return &DateTimeValue{
-				raw:   t.String(),
-				value: t,
-			}
-		case "today":
-			t := time.Now()
-			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
-			return &DateTimeValue{
-				raw:   t.String(),
-				value: t,
-			}
-		case "now":
-			t := time.Now()
-			return &DateTimeValue{
-				raw:   t.String(),
-				value: t,
-			}
-		case "tomorrow":
-			t := time.Now().Add(24 * time.Hour)
-			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
-			return &DateTimeValue{
-				raw:   t.String(),
-				value: t,
-			}
+		return vr.dateTime()
+	case Value:
+	default:
+		// Anything more complicated than a value, like an arithmetic
+		// expression, is evaluated. If that fails the expression is returned
+		// as is, and won't compare to anything.
+		if value, err := evaluateValue(environment, v); err == nil {
+			return value
 		}
 	}
 	return v
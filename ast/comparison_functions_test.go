@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jacobsimpson/jt/datetime"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
@@ -88,6 +89,23 @@ func Test_lt(t *testing.T) {
 		{NewStringValue("'jkl'"), NewStringValue("'kkl'") /*       */, true},
 		{NewStringValue("'jkl'"), NewStringValue("'jkl'") /*       */, false},
 		{NewStringValue("'jkl'"), NewStringValue("'ikl'") /*       */, false},
+
+		{mustDateTime(t, "2013T"), &AnyValue{"2013-12-31T23:59"} /* */, false},
+		{mustDateTime(t, "2013T"), &AnyValue{"2014-01-01T00:00"} /* */, true},
+		{&AnyValue{"2012-12-31T23:59"}, mustDateTime(t, "2013T") /* */, true},
+		{&AnyValue{"2013-01-01T00:00"}, mustDateTime(t, "2013T") /* */, false},
+		{mustDateTime(t, "2013-06T"), mustDateTime(t, "2013T") /*   */, false},
+		{mustDateTime(t, "2012-06T"), mustDateTime(t, "2013T") /*   */, true},
+
+		{mustDuration(t, "5m"), &AnyValue{"1h"} /*                 */, true},
+		{mustDuration(t, "5m"), &AnyValue{"5m"} /*                 */, false},
+		{mustDuration(t, "5m"), &AnyValue{"abc"} /*                */, false},
+		{mustDuration(t, "5m"), mustDuration(t, "300s") /*         */, false},
+		{mustDuration(t, "5m"), mustDuration(t, "1D") /*           */, true},
+		{mustDuration(t, "1M"), mustDuration(t, "29D") /*          */, false},
+		{mustDuration(t, "1M"), mustDuration(t, "31D") /*          */, true},
+		{mustDuration(t, "5m"), &IntegerValue{"6", 6} /*           */, false},
+		{&AnyValue{"2h"} /*    */, mustDuration(t, "3h") /*        */, true},
 	}
 
 	for _, test := range tests {
@@ -180,6 +198,18 @@ func Test_gt(t *testing.T) {
 		{NewStringValue("'jkl'"), NewStringValue("'kkl'") /*       */, false},
 		{NewStringValue("'jkl'"), NewStringValue("'jkl'") /*       */, false},
 		{NewStringValue("'jkl'"), NewStringValue("'ikl'") /*       */, true},
+
+		{mustDateTime(t, "2013T"), &AnyValue{"2012-12-31T23:59"} /* */, true},
+		{mustDateTime(t, "2013T"), &AnyValue{"2013-01-01T00:00"} /* */, false},
+		{&AnyValue{"2013-12-31T23:59"}, mustDateTime(t, "2013T") /* */, false},
+		{&AnyValue{"2014-01-01T00:00"}, mustDateTime(t, "2013T") /* */, true},
+		{mustDateTime(t, "2014-01T"), mustDateTime(t, "2013T") /*   */, true},
+
+		{mustDuration(t, "2h"), &AnyValue{"1h"} /*                 */, true},
+		{mustDuration(t, "2h"), &AnyValue{"2h"} /*                 */, false},
+		{mustDuration(t, "2h"), mustDuration(t, "90m") /*          */, true},
+		{mustDuration(t, "1Y"), mustDuration(t, "12M") /*          */, false},
+		{&AnyValue{"3D"} /*    */, mustDuration(t, "2D") /*        */, true},
 	}
 
 	for _, test := range tests {
@@ -202,7 +232,7 @@ func Test_eq(t *testing.T) {
 	}{
 		{
 			&Environment{
-				Row: &Row{1, []string{"whole 8", "whole", "8"}},
+				Row: &Row{LineNumber: 1, Columns: []string{"whole 8", "whole", "8"}},
 			},
 			&VarValue{"%2"},
 			&IntegerValue{raw: "1000", value: 8},
@@ -210,7 +240,7 @@ func Test_eq(t *testing.T) {
 		},
 		{
 			&Environment{
-				Row: &Row{1, []string{"whole 8", "whole", "7"}},
+				Row: &Row{LineNumber: 1, Columns: []string{"whole 8", "whole", "7"}},
 			},
 			&VarValue{"%2"},
 			&IntegerValue{raw: "1000", value: 8},
@@ -228,6 +258,82 @@ func Test_eq(t *testing.T) {
 			&StringValue{raw: "abcd", value: "abcd"},
 			false,
 		},
+		{
+			&Environment{
+				Row: &Row{LineNumber: 1, Columns: []string{"whole 2013-06-15T10:00", "whole", "2013-06-15T10:00"}},
+			},
+			&VarValue{"%2"},
+			mustDateTime(t, "2013T"),
+			true,
+		},
+		{
+			&Environment{
+				Row: &Row{LineNumber: 1, Columns: []string{"whole 2014-01-01T00:00", "whole", "2014-01-01T00:00"}},
+			},
+			&VarValue{"%2"},
+			mustDateTime(t, "2013T"),
+			false,
+		},
+		{
+			&Environment{},
+			mustDateTime(t, "2013-06-15T10"),
+			mustDateTime(t, "2013-06T"),
+			true,
+		},
+		{
+			&Environment{},
+			mustDuration(t, "2h"),
+			mustDuration(t, "120m"),
+			true,
+		},
+		{
+			&Environment{
+				Row: &Row{LineNumber: 1, Columns: []string{"whole 1D", "whole", "1D"}},
+			},
+			&VarValue{"%2"},
+			mustDuration(t, "24h"),
+			true,
+		},
+		{
+			&Environment{},
+			&IntegerValue{"3", 3},
+			&IntegerValue{"3", 3},
+			true,
+		},
+		{
+			&Environment{},
+			&IntegerValue{"3", 3},
+			mustDouble(t, "3.0"),
+			true,
+		},
+		{
+			&Environment{},
+			mustDouble(t, "3.5"),
+			&IntegerValue{"3", 3},
+			false,
+		},
+		{
+			&Environment{},
+			NewBooleanValue(true),
+			NewBooleanValue(true),
+			true,
+		},
+		{
+			&Environment{
+				Row: &Row{LineNumber: 1, Columns: []string{"whole False", "whole", "False"}},
+			},
+			&VarValue{"%2"},
+			NewBooleanValue(false),
+			true,
+		},
+		{
+			&Environment{
+				Row: &Row{LineNumber: 1, Columns: []string{"whole 0", "whole", "0"}},
+			},
+			&VarValue{"%2"},
+			NewBooleanValue(false),
+			false,
+		},
 	}
 
 	for _, test := range tests {
@@ -256,8 +362,9 @@ func TestVariableResolution(t *testing.T) {
 			},
 			&VarValue{"varname"},
 			&DateTimeValue{
-				"2010-10-11T06:45",
-				time.Date(2010, 10, 11, 6, 45, 0, 0, time.Now().Location()),
+				raw:         "2010-10-11T06:45",
+				value:       time.Date(2010, 10, 11, 6, 45, 0, 0, time.Now().Location()),
+				granularity: datetime.Minute,
 			},
 			true,
 		},
@@ -310,6 +417,28 @@ func TestVariableResolution(t *testing.T) {
 	}
 }
 
+func Test_ne(t *testing.T) {
+	tests := []struct {
+		left  Value
+		right Value
+		want  bool
+	}{
+		{&AnyValue{"2012-12-31T23:59"}, mustDateTime(t, "2013T"), true},
+		{&AnyValue{"2013-06-01T10:00"}, mustDateTime(t, "2013T"), false},
+		{&AnyValue{"abc"} /*        */, mustDateTime(t, "2013T"), true},
+		{mustDateTime(t, "2013T"), &AnyValue{"abc"} /*        */, true},
+		{&AnyValue{"abc"} /*        */, &IntegerValue{"13", 13}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s != %s", test.left, test.right), func(t *testing.T) {
+			got := ne(&Environment{}, test.left, test.right)
+
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
 func mustDateTime(t *testing.T, v string) Value {
 	t.Helper()
 
@@ -321,6 +450,17 @@ func mustDateTime(t *testing.T, v string) Value {
 	return d
 }
 
+func mustDuration(t *testing.T, v string) Value {
+	t.Helper()
+
+	d, err := NewDurationValue(v)
+	if err != nil {
+		t.Fatalf("Unable to convert %q to a duration: %+v", v, err)
+	}
+
+	return d
+}
+
 func mustDouble(t *testing.T, v string) Value {
 	t.Helper()
 
@@ -331,3 +471,28 @@ func mustDouble(t *testing.T, v string) Value {
 
 	return &DoubleValue{v, &d}
 }
+
+func Test_leAndGe(t *testing.T) {
+	tests := []struct {
+		left  Value
+		right Value
+		le    bool
+		ge    bool
+	}{
+		{&IntegerValue{"3", 3}, &IntegerValue{"3", 3}, true, true},
+		{&IntegerValue{"2", 2}, mustDouble(t, "2.5"), true, false},
+		{mustDouble(t, "2.5"), mustDouble(t, "2.5"), true, true},
+		{mustDouble(t, "3.5"), &IntegerValue{"3", 3}, false, true},
+		{NewStringValue(`"abc"`), NewStringValue(`"abc"`), true, true},
+		{NewStringValue(`"abd"`), NewStringValue(`"abc"`), false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v <=> %v", test.left, test.right), func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.le, le(&Environment{}, test.left, test.right))
+			assert.Equal(test.ge, ge(&Environment{}, test.left, test.right))
+		})
+	}
+}
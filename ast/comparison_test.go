@@ -0,0 +1,86 @@
+package ast
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingExpression is an Expression that can not be evaluated. It is used to
+// make sure the boolean operators don't evaluate more than they need to.
+type failingExpression struct{}
+
+func (e *failingExpression) Evaluate(environment *Environment) (interface{}, error) {
+	return nil, fmt.Errorf("failingExpression should not have been evaluated")
+}
+
+func (e *failingExpression) String() string {
+	return "fail"
+}
+
+func TestBooleanComparisons(t *testing.T) {
+	yes := &Comparison{&IntegerValue{"1", 1}, EQ_Operator, &AnyValue{"1"}}
+	no := &Comparison{&IntegerValue{"1", 1}, EQ_Operator, &AnyValue{"2"}}
+
+	tests := []struct {
+		expression Expression
+		want       interface{}
+	}{
+		{&AndComparison{yes, yes}, true},
+		{&AndComparison{yes, no}, false},
+		{&AndComparison{no, yes}, false},
+		{&AndComparison{no, &failingExpression{}}, false},
+		{&OrComparison{yes, no}, true},
+		{&OrComparison{no, yes}, true},
+		{&OrComparison{no, no}, false},
+		{&OrComparison{yes, &failingExpression{}}, true},
+		{&OrComparison{no, &AndComparison{yes, yes}}, true},
+		{NewNegativeExpression(&OrComparison{no, no}), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression.String(), func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.expression.Evaluate(&Environment{})
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
+
+// countedFailure is an Expression that can not be evaluated, and counts how
+// many times it was tried.
+type countedFailure struct {
+	count int
+}
+
+func (e *countedFailure) Evaluate(environment *Environment) (interface{}, error) {
+	e.count++
+	return nil, fmt.Errorf("countedFailure can not be evaluated")
+}
+
+func (e *countedFailure) String() string {
+	return "counted"
+}
+
+// An operand that can't be evaluated doesn't compare to anything, and is only
+// evaluated once, so that any side effects of it only happen once.
+func TestComparisonOperandEvaluatedOnce(t *testing.T) {
+	for _, operator := range []Operator{LT_Operator, LE_Operator, EQ_Operator, NE_Operator, GE_Operator, GT_Operator} {
+		t.Run(operator.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			left := &countedFailure{}
+			right := &countedFailure{}
+
+			got, err := (&Comparison{left, operator, right}).Evaluate(&Environment{})
+
+			assert.NoError(err)
+			assert.Equal(false, got)
+			assert.Equal(1, left.count)
+			assert.Equal(1, right.count)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+)
+
+// If evaluates Then when the Condition is true, and Else otherwise. Its value
+// is the value of the block that was evaluated, or null if the condition is
+// false and there is no Else. Else is a Block, or another If for an
+// `else if`.
+//
+//	if %2 > 10 { "big" } else { "small" }
+type If struct {
+	Condition Expression
+	Then      *Block
+	Else      Expression
+}
+
+func (e *If) Evaluate(environment *Environment) (interface{}, error) {
+	ok, err := isTrue(environment, e.Condition)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return e.Then.Evaluate(environment)
+	}
+	if e.Else != nil {
+		return e.Else.Evaluate(environment)
+	}
+	return nil, nil
+}
+
+func (e *If) String() string {
+	if e.Else == nil {
+		return fmt.Sprintf("if %s %s", e.Condition, e.Then)
+	}
+	return fmt.Sprintf("if %s %s else %s", e.Condition, e.Then, e.Else)
+}
+
+// For evaluates the Body once for each value of a list, set or tuple, or each
+// key of a map, with the value assigned to the variable Name. A loop doesn't
+// have a value.
+//
+//	for part in %1.split(",") { println(part) }
+type For struct {
+	Name string
+	List Expression
+	Body *Block
+}
+
+func (e *For) Evaluate(environment *Environment) (interface{}, error) {
+	v, err := evaluateValue(environment, e.List)
+	if err != nil {
+		return nil, err
+	}
+	if isNull(v) {
+		return nil, nil
+	}
+	values, ok := elements(v)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a collection", e.List)
+	}
+	for _, value := range values {
+		environment.Set(e.Name, value)
+		if _, err := e.Body.Evaluate(environment); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (e *For) String() string {
+	return fmt.Sprintf("for %s in %s %s", e.Name, e.List, e.Body)
+}
+
+// While evaluates the Body for as long as the Condition is true. A loop
+// doesn't have a value.
+type While struct {
+	Condition Expression
+	Body      *Block
+}
+
+func (e *While) Evaluate(environment *Environment) (interface{}, error) {
+	for {
+		ok, err := isTrue(environment, e.Condition)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+		if _, err := e.Body.Evaluate(environment); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (e *While) String() string {
+	return fmt.Sprintf("while %s %s", e.Condition, e.Body)
+}
+
+// ErrNext is what the next statement evaluates to. It stops the block, like
+// any other error, and the rules aren't applied to the rest of the line.
+var ErrNext = errors.New("next can only be used in a rule")
+
+// Next skips to the next line of input.
+type Next struct{}
+
+func (e *Next) Evaluate(environment *Environment) (interface{}, error) {
+	return nil, ErrNext
+}
+
+func (e *Next) String() string {
+	return "next"
+}
+
+// ExitError is what the exit statement evaluates to. It stops the block, like
+// any other error, and the program stops reading input. The END blocks are
+// still executed, unless the exit is in one of them.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit %d", e.Code)
+}
+
+// Exit stops the program, with the exit code of the Code expression, or 0
+// when there isn't one.
+type Exit struct {
+	Code Expression
+}
+
+func (e *Exit) Evaluate(environment *Environment) (interface{}, error) {
+	if e.Code == nil {
+		return nil, &ExitError{0}
+	}
+	v, err := evaluateValue(environment, e.Code)
+	if err != nil {
+		return nil, err
+	}
+	code, err := convertInt(environment, []Value{v})
+	if err != nil {
+		return nil, fmt.Errorf("exit: %v", err)
+	}
+	return nil, &ExitError{int(code.(*IntegerValue).value)}
+}
+
+func (e *Exit) String() string {
+	if e.Code == nil {
+		return "exit"
+	}
+	return fmt.Sprintf("exit %s", e.Code)
+}
+
+// isControl reports whether an error is a next or an exit, rather than a
+// failure.
+func isControl(err error) bool {
+	var exit *ExitError
+	return errors.Is(err, ErrNext) || errors.As(err, &exit)
+}
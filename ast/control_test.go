@@ -0,0 +1,119 @@
+package ast
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControlFlow(t *testing.T) {
+	environment := &Environment{
+		Row: &Row{LineNumber: 1, Columns: []string{"a 5", "a", "5"}},
+	}
+	big := &Comparison{NewVarValue("%2"), GT_Operator, &IntegerValue{"10", 10}}
+	block := func(statements ...Expression) *Block {
+		return &Block{Statements: statements}
+	}
+
+	tests := []struct {
+		name       string
+		expression Expression
+		want       interface{}
+	}{
+		{"if", &If{NewNullValue(), block(NewStringValue(`"then"`)), nil}, nil},
+		{"else", &If{big, block(NewStringValue(`"big"`)), block(NewStringValue(`"small"`))}, "small"},
+		{"else if", &If{big, block(NewStringValue(`"big"`)),
+			&If{NewVarValue("%1"), block(NewVarValue("%1")), nil}}, &AnyValue{"a"}},
+		{"for", block(
+			&For{"x", NewListValue([]Value{&IntegerValue{"1", 1}, &IntegerValue{"2", 2}}), block(
+				&Assignment{"total", &Coalesce{&Arithmetic{NewVarValue("total"), ADD_Operator, NewVarValue("x")}, NewVarValue("x")}},
+			)},
+			NewVarValue("total"),
+		), &IntegerValue{"3", 3}},
+		{"for null", &For{"x", NewVarValue("%3"), block()}, nil},
+		{"while", block(
+			&Assignment{"i", &IntegerValue{"0", 0}},
+			&While{&Comparison{NewVarValue("i"), LT_Operator, &IntegerValue{"3", 3}}, block(
+				&Assignment{"i", &Arithmetic{NewVarValue("i"), ADD_Operator, &IntegerValue{"1", 1}}},
+			)},
+			NewVarValue("i"),
+		), &IntegerValue{"3", 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.expression.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
+
+func TestNextAndExit(t *testing.T) {
+	environment := NewEnvironment()
+	environment.Functions = map[string]*Function{
+		"stop": {Name: "stop", Body: &Block{Statements: []Expression{&Exit{&AnyValue{"4"}}}}},
+	}
+
+	_, err := (&Block{Statements: []Expression{&Next{}, &failingExpression{}}}).Evaluate(environment)
+	assert.True(t, errors.Is(err, ErrNext))
+
+	_, err = (&Exit{}).Evaluate(environment)
+	assert.Equal(t, &ExitError{0}, err)
+
+	var exit *ExitError
+	_, err = (&Assignment{"x", &Command{Name: "stop"}}).Evaluate(environment)
+	assert.True(t, errors.As(err, &exit))
+	assert.Equal(t, 4, exit.Code)
+
+	_, err = (&If{&Command{Name: "stop"}, &Block{}, nil}).Evaluate(environment)
+	assert.True(t, errors.As(err, &exit))
+
+	_, err = (&For{"x", &AnyValue{"a"}, &Block{}}).Evaluate(environment)
+	assert.Error(t, err)
+}
+
+func TestNextAndExitInSelections(t *testing.T) {
+	environment := NewEnvironment()
+	environment.Row = NewRow(1, "a 5")
+	environment.Functions = map[string]*Function{
+		"stop": {Name: "stop", Body: &Block{Statements: []Expression{&Exit{&AnyValue{"3"}}}}},
+		"skip": {Name: "skip", Body: &Block{Statements: []Expression{&Next{}}}},
+	}
+	stop := &Command{Name: "stop"}
+	skip := &Command{Name: "skip"}
+
+	tests := []struct {
+		name       string
+		expression Expression
+		next       bool
+	}{
+		{"rule", &Rule{Selection: stop, Block: &Block{}}, false},
+		{"comparison", &Comparison{stop, EQ_Operator, &IntegerValue{"1", 1}}, false},
+		{"and", &AndComparison{NewBooleanValue(true), stop}, false},
+		{"or", &OrComparison{NewBooleanValue(false), stop}, false},
+		{"not", NewNegativeExpression(stop), false},
+		{"range", &RangeSelection{Start: stop, End: stop}, false},
+		{"coalesce", &Coalesce{stop, NewStringValue(`"z"`)}, false},
+		{"next in rule", &Rule{Selection: skip, Block: &Block{}}, true},
+		{"next in coalesce", &Coalesce{skip, NewStringValue(`"z"`)}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.expression.Evaluate(environment)
+
+			if test.next {
+				assert.True(t, errors.Is(err, ErrNext))
+				return
+			}
+			var exit *ExitError
+			assert.True(t, errors.As(err, &exit))
+			assert.Equal(t, 3, exit.Code)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jacobsimpson/jt/datetime"
+	"github.com/shopspring/decimal"
+)
+
+// These are the coercions used when a value from the input is compared to, or
+// combined with, a value of another type. The conversion functions make the
+// same coercions available to a program, `int(%2)`, `decimal(%3)`,
+// `date(%5)`, `string(%1)` and `regex(%1)`.
+//
+// A value that can't be converted is an error. Inside a comparison the error
+// fails the comparison, so `int(%2) > 5` doesn't select a line where %2 isn't
+// an integer, just like `%2 > 5` doesn't.
+
+func parseInt(s string) (int64, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == '_' {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > 2 {
+		if strings.HasPrefix(s, "0x") {
+			return strconv.ParseInt(s, 0, 64)
+		}
+	}
+	if len(s) > 2 {
+		if strings.HasPrefix(s, "0b") {
+			return strconv.ParseInt(s[2:], 2, 64)
+		}
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// parseDecimal parses a decimal, or any integer that parseInt can parse.
+func parseDecimal(s string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		i, err := parseInt(s)
+		if err != nil {
+			return decimal.Decimal{}, err
+		}
+		return decimal.New(i, 0), nil
+	}
+	return d, nil
+}
+
+// parseBool parses true or false, in any case.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not a boolean", s)
+}
+
+// parseDateTime parses a date/time in any of the formats a value from the
+// input can be coerced from.
+func parseDateTime(s string) (datetime.Period, error) {
+	return datetime.ParsePeriod(datetime.CoercionFormats, s)
+}
+
+// convertInt converts a value to an integer. A decimal is truncated toward
+// zero, text has to be an integer, in any of the forms of an integer literal.
+//
+//	int(%2)
+func convertInt(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 1); err != nil {
+		return nil, err
+	}
+	switch v := parameters[0].(type) {
+	case *IntegerValue:
+		return v, nil
+	case *DoubleValue:
+		truncated := v.value.Truncate(0)
+		if !truncated.Equal(decimal.NewFromInt(truncated.IntPart())) {
+			return nil, fmt.Errorf("%s is too large for an integer", v)
+		}
+		return newInteger(truncated.IntPart()), nil
+	case *AnyValue, *StringValue:
+		s := strings.TrimSpace(v.String())
+		i, err := parseInt(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", s)
+		}
+		return &IntegerValue{raw: s, value: i}, nil
+	}
+	return nil, fmt.Errorf("%s can not be converted to an integer", parameters[0])
+}
+
+// convertDecimal converts a value to a decimal.
+//
+//	decimal(%3)
+func convertDecimal(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 1); err != nil {
+		return nil, err
+	}
+	switch v := parameters[0].(type) {
+	case *DoubleValue:
+		return v, nil
+	case *IntegerValue:
+		return newDouble(decimal.NewFromInt(v.value)), nil
+	case *AnyValue, *StringValue:
+		s := strings.TrimSpace(v.String())
+		d, err := parseDecimal(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a decimal", s)
+		}
+		return &DoubleValue{raw: s, value: &d}, nil
+	}
+	return nil, fmt.Errorf("%s can not be converted to a decimal", parameters[0])
+}
+
+// convertString converts any value to a string, so that it is compared as a
+// string, rather than being coerced to the type it is compared to.
+//
+//	string(%1) < "10"
+func convertString(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 1); err != nil {
+		return nil, err
+	}
+	switch v := parameters[0].(type) {
+	case *StringValue:
+		return v, nil
+	case *NullValue:
+		return nil, fmt.Errorf("%s can not be converted to a string", v)
+	}
+	return toValue(parameters[0].String())
+}
+
+// convertDate converts a value to a date/time. Without a pattern, text can be
+// in any of the formats a value from the input can be coerced from. With a
+// pattern, the text has to match the strftime style pattern. Either way, the
+// date/time is as precise as the text it was converted from, so
+// `date("2019-06-01")` is the whole day, just like the literal `2019-06-01T`.
+//
+//	date(%5)
+//	date(%5, "%d/%m/%Y")
+func convertDate(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 2); err != nil {
+		return nil, err
+	}
+	if d, ok := parameters[0].(*DateTimeValue); ok && len(parameters) == 1 {
+		return d, nil
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s can not be converted to a date", parameters[0])
+	}
+	var period datetime.Period
+	if len(parameters) == 1 {
+		period, err = parseDateTime(s)
+	} else {
+		var pattern string
+		if pattern, err = toText(parameters[1]); err != nil {
+			return nil, err
+		}
+		period, err = datetime.Parse(pattern, s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DateTimeValue{raw: s, value: period.Start, granularity: period.Granularity}, nil
+}
+
+// convertRegex compiles text into a regular expression.
+//
+//	%0.contains(regex($PATTERN))
+func convertRegex(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 1); err != nil {
+		return nil, err
+	}
+	if re, ok := parameters[0].(*RegexpValue); ok {
+		return re, nil
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, fmt.Errorf("%s can not be converted to a regular expression", parameters[0])
+	}
+	return NewRegexpValue(s)
+}
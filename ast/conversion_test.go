@@ -0,0 +1,108 @@
+package ast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jacobsimpson/jt/datetime"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversions(t *testing.T) {
+	dbl := func(s string) Value {
+		d := decimal.RequireFromString(s)
+		return &DoubleValue{raw: s, value: &d}
+	}
+	re := func(s string) Value {
+		r, _ := NewRegexpValue(s)
+		return r
+	}
+	day := mustDateTime(t, "2019-06-01T")
+
+	tests := []struct {
+		name       string
+		function   Builtin
+		parameters []Value
+		want       interface{}
+	}{
+		{"int", convertInt, []Value{&AnyValue{"42"}}, &IntegerValue{raw: "42", value: 42}},
+		{"int hex", convertInt, []Value{&AnyValue{"0x1F"}}, &IntegerValue{raw: "0x1F", value: 31}},
+		{"int underscores", convertInt, []Value{NewStringValue(`" 1_000 "`)}, &IntegerValue{raw: "1_000", value: 1000}},
+		{"int decimal", convertInt, []Value{dbl("-3.75")}, newInteger(-3)},
+		{"int integer", convertInt, []Value{newInteger(7)}, newInteger(7)},
+		{"decimal", convertDecimal, []Value{&AnyValue{"2.50"}}, dbl("2.50")},
+		{"decimal integer text", convertDecimal, []Value{&AnyValue{"0x10"}}, &DoubleValue{raw: "0x10", value: func() *decimal.Decimal { d := decimal.New(16, 0); return &d }()}},
+		{"decimal integer", convertDecimal, []Value{newInteger(3)}, newDouble(decimal.New(3, 0))},
+		{"string", convertString, []Value{&AnyValue{"10"}}, &StringValue{raw: `"10"`, value: "10"}},
+		{"string integer", convertString, []Value{newInteger(10)}, &StringValue{raw: `"10"`, value: "10"}},
+		{"date", convertDate, []Value{&AnyValue{"2019-06-01"}}, &DateTimeValue{raw: "2019-06-01", value: time.Date(2019, 6, 1, 0, 0, 0, 0, time.Local), granularity: datetime.Day}},
+		{"date pattern", convertDate, []Value{&AnyValue{"01/06/2019"}, NewStringValue(`"%d/%m/%Y"`)}, &DateTimeValue{raw: "01/06/2019", value: time.Date(2019, 6, 1, 0, 0, 0, 0, time.Local), granularity: datetime.Day}},
+		{"date date", convertDate, []Value{day}, day},
+		{"regex", convertRegex, []Value{&AnyValue{"a.c"}}, re("a.c")},
+		{"regex regex", convertRegex, []Value{re("a+")}, re("a+")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.function(NewEnvironment(), test.parameters)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
+
+func TestConversionErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		function   Builtin
+		parameters []Value
+	}{
+		{"int of text", convertInt, []Value{&AnyValue{"abc"}}},
+		{"int of decimal text", convertInt, []Value{&AnyValue{"3.5"}}},
+		{"int of empty", convertInt, []Value{&AnyValue{""}}},
+		{"int of date", convertInt, []Value{mustDateTime(t, "2019T")}},
+		{"decimal of text", convertDecimal, []Value{&AnyValue{"1.2.3"}}},
+		{"date of text", convertDate, []Value{&AnyValue{"yesterday-ish"}}},
+		{"date with wrong pattern", convertDate, []Value{&AnyValue{"2019-06-01"}, NewStringValue(`"%d/%m/%Y"`)}},
+		{"regex of bad pattern", convertRegex, []Value{&AnyValue{"a(b"}}},
+		{"string of null", convertString, []Value{NewNullValue()}},
+		{"too many parameters", convertString, []Value{&AnyValue{"a"}, &AnyValue{"b"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.function(NewEnvironment(), test.parameters)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+// A converted date prints the way it would be written as a literal, as precise
+// as the text it was converted from.
+func TestConvertedDateString(t *testing.T) {
+	tests := []struct {
+		parameters []Value
+		want       string
+	}{
+		{[]Value{&AnyValue{"2019-06-01"}}, "2019-06-01T"},
+		{[]Value{&AnyValue{"2019-06-01T10:15"}}, "2019-06-01T10:15"},
+		{[]Value{&AnyValue{"05/06/2024"}, NewStringValue(`"%d/%m/%Y"`)}, "2024-06-05T"},
+		{[]Value{&AnyValue{"05/06/2024 10:15"}, NewStringValue(`"%d/%m/%Y %H:%M"`)}, "2024-06-05T10:15"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := convertDate(NewEnvironment(), test.parameters)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got.(Value).String())
+		})
+	}
+}
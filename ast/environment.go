@@ -7,15 +7,93 @@ import (
 	"strings"
 )
 
+// Environment holds everything an expression might refer to while it is
+// being evaluated. The Row changes with every line of input, the Variables are
+// kept for the whole run of the program. Substrings and lengths count
+// characters, unless Bytes is set, in which case they count bytes. Functions
+// are the functions the program defines.
+//
+// A call to a function gets a scope of its own, an Environment with the
+// program's Environment as its parent. Variables are looked up in the scope
+// first, then in the parent.
 type Environment struct {
 	Row       *Row
 	Variables map[string]Value
+	Bytes     bool
+	Functions map[string]*Function
+
+	parent *Environment
+	depth  int
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{
+		Variables: map[string]Value{},
+	}
+}
+
+// NewScope makes the scope for a function call. The scope sees the variables
+// of the program, but not those of the function that made the call.
+func (e *Environment) NewScope() *Environment {
+	global := e
+	for global.parent != nil {
+		global = global.parent
+	}
+	return &Environment{
+		Row:       e.Row,
+		Variables: map[string]Value{},
+		Bytes:     e.Bytes,
+		Functions: e.Functions,
+		parent:    global,
+		depth:     e.depth + 1,
+	}
+}
+
+// Set assigns a value to a variable. Inside a function, the variable is local
+// to the call.
+func (e *Environment) Set(name string, value Value) {
+	if e.Variables == nil {
+		e.Variables = map[string]Value{}
+	}
+	e.Variables[name] = value
 }
 
+// SetColumn changes a column of the current line, `%3 = %3 + 10`, and %0 is
+// put back together from the columns. The value is converted to text the same
+// way it would be printed.
+func (e *Environment) SetColumn(name string, value Value) error {
+	if e.Row == nil {
+		return fmt.Errorf("there is no line to set %s in", name)
+	}
+	i, err := strconv.Atoi(name[1:])
+	if err != nil {
+		return fmt.Errorf("%s can not be set", name)
+	}
+	return e.Row.Set(i, fmt.Sprint(printable(value)))
+}
+
+// isUnset reports whether vr is a variable that hasn't been assigned a value,
+// in the scope or in its parent. A column or an environment variable is never
+// an unset variable, even when it is missing.
+func (e *Environment) isUnset(vr *VarValue) bool {
+	if strings.HasPrefix(vr.name, "%") || strings.HasPrefix(vr.name, "$") {
+		return false
+	}
+	for scope := e; scope != nil; scope = scope.parent {
+		if _, ok := scope.Variables[vr.name]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve finds the value of a column, an environment variable or a variable.
+// A column that isn't in the line, or an environment variable or variable that
+// isn't set, is null.
 func (e *Environment) Resolve(vr *VarValue) Expression {
 	if strings.HasPrefix(vr.name, "%") {
 		if e.Row == nil {
-			return &AnyValue{""}
+			return NewNullValue()
 		}
 		id := vr.name[1:]
 		if id == "#" {
@@ -26,21 +104,30 @@ func (e *Environment) Resolve(vr *VarValue) Expression {
 			return &AnyValue{}
 		}
 		i := int(l)
-		if i >= len(e.Row.Columns) || len(e.Row.Columns)+i <= 1 {
-			return &AnyValue{""}
-		}
 		if i < 0 {
+			// Negative columns count back from the last column, and never
+			// reach the whole line.
+			if len(e.Row.Columns)+i < 1 {
+				return NewNullValue()
+			}
 			return &AnyValue{e.Row.Columns[len(e.Row.Columns)+i]}
 		}
+		if i >= len(e.Row.Columns) {
+			return NewNullValue()
+		}
 		return &AnyValue{e.Row.Columns[i]}
 	}
 	if strings.HasPrefix(vr.name, "$") {
-		return &AnyValue{os.Getenv(vr.name[1:])}
+		if v, ok := os.LookupEnv(vr.name[1:]); ok {
+			return &AnyValue{v}
+		}
+		return NewNullValue()
 	}
-	return e.Variables[vr.name]
-}
-
-type Row struct {
-	LineNumber int
-	Columns    []string
+	if v, ok := e.Variables[vr.name]; ok {
+		return v
+	}
+	if e.parent != nil {
+		return e.parent.Resolve(vr)
+	}
+	return NewNullValue()
 }
@@ -16,7 +16,7 @@ func TestEnvironment(t *testing.T) {
 		{
 			"get positive valid column from environment",
 			&Environment{
-				Row: &Row{1, []string{"whole line 8", "whole", "line", "7"}},
+				Row: &Row{LineNumber: 1, Columns: []string{"whole line 8", "whole", "line", "7"}},
 			},
 			NewVarValue("%2").(*VarValue),
 			&AnyValue{"line"},
@@ -24,7 +24,7 @@ func TestEnvironment(t *testing.T) {
 		{
 			"get negative valid column from environment",
 			&Environment{
-				Row: &Row{10, []string{"whole line 8", "whole", "line", "7"}},
+				Row: &Row{LineNumber: 10, Columns: []string{"whole line 8", "whole", "line", "7"}},
 			},
 			NewVarValue("%-1").(*VarValue),
 			&AnyValue{"7"},
@@ -32,23 +32,45 @@ func TestEnvironment(t *testing.T) {
 		{
 			"get positive invalid column from environment",
 			&Environment{
-				Row: &Row{10, []string{"whole line 8", "whole", "line", "7"}},
+				Row: &Row{LineNumber: 10, Columns: []string{"whole line 8", "whole", "line", "7"}},
 			},
 			NewVarValue("%6").(*VarValue),
-			&AnyValue{""},
+			&NullValue{},
 		},
 		{
 			"get negative invalid column from environment",
 			&Environment{
-				Row: &Row{10, []string{"whole line 8", "whole", "line", "7"}},
+				Row: &Row{LineNumber: 10, Columns: []string{"whole line 8", "whole", "line", "7"}},
 			},
 			NewVarValue("%-6").(*VarValue),
+			&NullValue{},
+		},
+		{
+			"get last column of a line with one column from environment",
+			&Environment{
+				Row: &Row{LineNumber: 10, Columns: []string{"whole", "whole"}},
+			},
+			NewVarValue("%-1").(*VarValue),
+			&AnyValue{"whole"},
+		},
+		{
+			"get empty line from environment",
+			&Environment{
+				Row: &Row{LineNumber: 10, Columns: []string{""}},
+			},
+			NewVarValue("%0").(*VarValue),
 			&AnyValue{""},
 		},
+		{
+			"get unset environment variable from environment",
+			NewEnvironment(),
+			NewVarValue("$JT_UNSET_VARIABLE").(*VarValue),
+			&NullValue{},
+		},
 		{
 			"get whole line from environment",
 			&Environment{
-				Row: &Row{10, []string{"whole line 8", "whole", "line", "7"}},
+				Row: &Row{LineNumber: 10, Columns: []string{"whole line 8", "whole", "line", "7"}},
 			},
 			NewVarValue("%0").(*VarValue),
 			&AnyValue{"whole line 8"},
@@ -61,6 +83,12 @@ func TestEnvironment(t *testing.T) {
 			NewVarValue("varname").(*VarValue),
 			&VarValue{"matching value"},
 		},
+		{
+			"get unassigned variable from environment",
+			NewEnvironment(),
+			NewVarValue("varname").(*VarValue),
+			&NullValue{},
+		},
 	}
 
 	for _, test := range tests {
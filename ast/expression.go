@@ -2,6 +2,8 @@ package ast
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 )
 
 type Expression interface {
@@ -15,10 +17,34 @@ type Expression interface {
 // referenced off the beginning of the underlying value (1 means one character
 // in from the left of a string), negative values are referenced off the end of
 // the underlying value (-1 means one character in from the right of a string).
+// A StartMarker or EndMarker takes the place of Start or End, finding the
+// boundary by searching the value instead.
 type RangeExpression struct {
-	Expression Expression
-	Start      *int
-	End        *int
+	Expression  Expression
+	Start       *int
+	End         *int
+	StartMarker *RangeMarker
+	EndMarker   *RangeMarker
+}
+
+// RangeMarker is a boundary of a range that is found by searching for a string
+// or a regular expression. The boundary is the start of the match, or the end
+// of the match if After is set. The search starts from the beginning of the
+// value, or from the end if Backward is set. The search for an EndMarker
+// starts after the text that matched the StartMarker, or from the Start
+// index. If there is no match, the boundary is the edge of the value, just as
+// if the marker had been left out.
+//
+//	s = "ab.cd.txt"
+//	s[:"."]      == "ab"
+//	s[:-"."]     == "ab.cd"
+//	s["."+:"."]  == "cd"
+//	s[-"."+:]    == "txt"
+//	s[:/txt/]    == "ab.cd."
+type RangeMarker struct {
+	Pattern  Value
+	Backward bool
+	After    bool
 }
 
 func NewRangeExpression(expression Expression, start, end *int) Expression {
@@ -30,39 +56,166 @@ func NewRangeExpression(expression Expression, start, end *int) Expression {
 }
 
 func (e *RangeExpression) Evaluate(environment *Environment) (interface{}, error) {
-	v, err := e.Expression.Evaluate(environment)
+	v, err := evaluateValue(environment, e.Expression)
 	if err != nil {
 		return nil, err
 	}
-	if s, ok := v.(*AnyValue); ok {
-		start := 0
-		if e.Start != nil {
-			start = *e.Start
+	switch s := v.(type) {
+	case *AnyValue:
+		// Part of a value that has no type yet doesn't have a type either.
+		return &AnyValue{e.slice(s.raw, environment.Bytes)}, nil
+	case *StringValue:
+		return toValue(e.slice(s.value, environment.Bytes))
+	case *ListValue:
+		if e.StartMarker != nil || e.EndMarker != nil {
+			return nil, fmt.Errorf("a list can only be sliced by position")
 		}
-		if start < 0 {
-			start = len(s.raw) + start
+		start, end := 0, len(s.values)
+		if e.Start != nil {
+			start = index(*e.Start, len(s.values))
 		}
-		end := len(s.raw)
 		if e.End != nil {
-			end = *e.End
-		}
-		if end < 0 {
-			end = len(s.raw) + end
+			end = index(*e.End, len(s.values))
 		}
 		if start > end {
-			return "", nil
+			start = end
 		}
-		return s.raw[start:end], nil
+		return NewListValue(s.values[start:end]), nil
+	case *NullValue:
+		return s, nil
 	}
 	return nil, fmt.Errorf("range can not be applied to %q", e.Expression)
 }
 
+// slice returns the part of s within the range. Indexes count characters,
+// rather than bytes, unless bytes is set, so a multi-byte character is never
+// cut in half.
+func (e *RangeExpression) slice(s string, bytes bool) string {
+	length := utf8.RuneCountInString(s)
+	offset := func(i int) int { return byteOffset(s, i) }
+	if bytes {
+		length = len(s)
+		offset = func(i int) int { return i }
+	}
+
+	// The search for the end marker starts after the text that matched the
+	// start marker, so that s[".":"."] doesn't find the same "." twice.
+	start, searchFrom := 0, 0
+	if e.StartMarker != nil {
+		if match := e.StartMarker.find(s, 0); match != nil {
+			start, searchFrom = match[0], match[1]
+			if e.StartMarker.After {
+				start = match[1]
+			}
+		}
+	} else if e.Start != nil {
+		start = offset(index(*e.Start, length))
+		searchFrom = start
+	}
+	end := len(s)
+	if e.EndMarker != nil {
+		if match := e.EndMarker.find(s, searchFrom); match != nil {
+			end = match[0]
+			if e.EndMarker.After {
+				end = match[1]
+			}
+		}
+	} else if e.End != nil {
+		end = offset(index(*e.End, length))
+	}
+	if start > end {
+		return ""
+	}
+	return s[start:end]
+}
+
+// index converts a position that can be negative, to count from the end, into
+// an offset that is within a value of the given length.
+func index(i, length int) int {
+	if i < 0 {
+		i = length + i
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+// byteOffset returns the offset of the byte that starts the i'th character of
+// s.
+func byteOffset(s string, i int) int {
+	for offset := range s {
+		if i == 0 {
+			return offset
+		}
+		i--
+	}
+	return len(s)
+}
+
+// find returns the offsets of the start and end of the text in s that
+// matches the marker, searching from offset, or nil if there is no match.
+func (m *RangeMarker) find(s string, offset int) []int {
+	var matches [][]int
+	switch p := m.Pattern.(type) {
+	case *RegexpValue:
+		matches = p.re.FindAllStringIndex(s[offset:], -1)
+	default:
+		pattern := p.Value().(string)
+		if pattern == "" {
+			return nil
+		}
+		for i := 0; ; {
+			j := strings.Index(s[offset+i:], pattern)
+			if j < 0 {
+				break
+			}
+			matches = append(matches, []int{i + j, i + j + len(pattern)})
+			i += j + len(pattern)
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	match := matches[0]
+	if m.Backward {
+		match = matches[len(matches)-1]
+	}
+	return []int{offset + match[0], offset + match[1]}
+}
+
 func (e *RangeExpression) SetExpression(expression Expression) {
 	e.Expression = expression
 }
 
 func (e *RangeExpression) String() string {
-	return fmt.Sprintf("%v[%d:%d]", e.Expression, e.Start, e.End)
+	bound := func(i *int, m *RangeMarker) string {
+		switch {
+		case m != nil:
+			return m.String()
+		case i != nil:
+			return fmt.Sprintf("%d", *i)
+		}
+		return ""
+	}
+	return fmt.Sprintf("%v[%s:%s]", e.Expression, bound(e.Start, e.StartMarker), bound(e.End, e.EndMarker))
+}
+
+func (m *RangeMarker) String() string {
+	s := m.Pattern.Raw()
+	if _, ok := m.Pattern.(*RegexpValue); ok {
+		s = "/" + s + "/"
+	}
+	if m.Backward {
+		s = "-" + s
+	}
+	if m.After {
+		s = s + "+"
+	}
+	return s
 }
 
 //
@@ -79,14 +232,8 @@ func NewNegativeExpression(expression Expression) Expression {
 }
 
 func (e *negativeExpression) Evaluate(environment *Environment) (interface{}, error) {
-	o, err := e.expression.Evaluate(environment)
-	if err != nil {
-		return nil, err
-	}
-	if v, ok := o.(bool); ok {
-		return !v, nil
-	}
-	return nil, fmt.Errorf("attempted to negate a non-boolean value")
+	ok, err := isTrue(environment, e.expression)
+	return !ok && err == nil, err
 }
 
 func (e *negativeExpression) String() string {
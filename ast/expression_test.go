@@ -2,10 +2,95 @@ package ast
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestExpressionInterface(*testing.T) {
 	var _ Expression = &Comparison{}
 	var _ Expression = &RangeExpression{}
+	var _ Expression = &Command{}
+	var _ Expression = &Assignment{}
+	var _ Expression = &AndComparison{}
+	var _ Expression = &OrComparison{}
+	var _ Expression = &RangeSelection{}
+	var _ Expression = &Arithmetic{}
 	var _ Expression = &negativeExpression{}
+	var _ Expression = &negationExpression{}
+	var _ Expression = &InterpolatedString{}
+}
+
+func TestRangeExpression(t *testing.T) {
+	intp := func(i int) *int { return &i }
+	str := func(s string, backward, after bool) *RangeMarker {
+		return &RangeMarker{Pattern: NewStringValue(`"` + s + `"`), Backward: backward, After: after}
+	}
+	re := func(s string) *RangeMarker {
+		r, _ := NewRegexpValue(s)
+		return &RangeMarker{Pattern: r}
+	}
+	tests := []struct {
+		rng  RangeExpression
+		want string
+	}{
+		{RangeExpression{}, "ab.cd.txt"},
+		{RangeExpression{Start: intp(0), End: intp(-1)}, "ab.cd.tx"},
+		{RangeExpression{Start: intp(-100), End: intp(100)}, "ab.cd.txt"},
+		{RangeExpression{Start: intp(5), End: intp(2)}, ""},
+		{RangeExpression{EndMarker: str(".", false, false)}, "ab"},
+		{RangeExpression{EndMarker: str(".", true, false)}, "ab.cd"},
+		{RangeExpression{EndMarker: str(".", false, true)}, "ab."},
+		{RangeExpression{StartMarker: str(".", false, false), EndMarker: str(".", false, false)}, ".cd"},
+		{RangeExpression{StartMarker: str(".", false, true), EndMarker: str(".", false, false)}, "cd"},
+		{RangeExpression{StartMarker: str(".", true, true)}, "txt"},
+		{RangeExpression{Start: intp(3), EndMarker: str(".", false, false)}, "cd"},
+		{RangeExpression{StartMarker: str("/", false, false)}, "ab.cd.txt"},
+		{RangeExpression{EndMarker: str("/", true, false)}, "ab.cd.txt"},
+		{RangeExpression{EndMarker: re("t.t")}, "ab.cd."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rng.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			environment := NewEnvironment()
+			environment.Row = &Row{LineNumber: 1, Columns: []string{"ab.cd.txt", "ab.cd.txt"}}
+			test.rng.Expression = NewVarValue("%1")
+
+			got, err := test.rng.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(&AnyValue{test.want}, got)
+		})
+	}
+}
+
+func TestRangeExpressionCharacters(t *testing.T) {
+	intp := func(i int) *int { return &i }
+	tests := []struct {
+		rng   RangeExpression
+		bytes bool
+		want  string
+	}{
+		{RangeExpression{End: intp(3)}, false, "日本語"},
+		{RangeExpression{Start: intp(-2)}, false, "👋!"},
+		{RangeExpression{Start: intp(1), End: intp(-2)}, false, "本語 "},
+		{RangeExpression{End: intp(3)}, true, "日"},
+		{RangeExpression{Start: intp(-1)}, true, "!"},
+		{RangeExpression{StartMarker: &RangeMarker{Pattern: NewStringValue(`" "`), After: true}, End: intp(-1)}, false, "👋"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rng.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			environment := NewEnvironment()
+			environment.Bytes = test.bytes
+			environment.Row = &Row{LineNumber: 1, Columns: []string{"日本語 👋!", "日本語 👋!"}}
+			test.rng.Expression = NewVarValue("%1")
+
+			got, err := test.rng.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(&AnyValue{test.want}, got)
+		})
+	}
 }
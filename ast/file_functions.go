@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// loadedLines are the sets made by lines, by file name, so that a file is
+// only read once, however many lines of input it is checked against.
+var loadedLines = struct {
+	sync.Mutex
+	sets map[string]*SetValue
+}{sets: map[string]*SetValue{}}
+
+// lines reads a file into a set of its lines. The lines are values that
+// haven't got a type yet, like the columns of the input, so they are coerced
+// when they are compared. The file is read the first time it is used, and the
+// same set is used after that.
+//
+//	%1 in lines("allowlist.txt")
+func lines(environment *Environment, parameters []Value) (interface{}, error) {
+	if len(parameters) != 1 {
+		return nil, fmt.Errorf("lines takes a file name, got %d parameters", len(parameters))
+	}
+	name, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+
+	loadedLines.Lock()
+	defer loadedLines.Unlock()
+	if set, ok := loadedLines.sets[name]; ok {
+		return set, nil
+	}
+	set, err := readLines(name)
+	if err != nil {
+		return nil, err
+	}
+	loadedLines.sets[name] = set
+	return set, nil
+}
+
+func readLines(name string) (*SetValue, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	set := &SetValue{newValueIndex(nil)}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		set.index.add(&AnyValue{scanner.Text()})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", name, err)
+	}
+	return set, nil
+}
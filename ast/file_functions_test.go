@@ -0,0 +1,33 @@
+package ast
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	assert := assert.New(t)
+	name := filepath.Join(t.TempDir(), "allowlist.txt")
+	assert.NoError(os.WriteFile(name, []byte("alice\nbob\r\n42\n"), 0644))
+	file := []Value{NewStringValue(`"` + name + `"`)}
+
+	got, err := lines(nil, file)
+
+	assert.NoError(err)
+	assert.Equal("{alice, bob, 42}", got.(Value).String())
+	assert.True(isIn(nil, &AnyValue{"bob"}, got.(Value)))
+	assert.True(isIn(nil, newInteger(42), got.(Value)))
+	assert.False(isIn(nil, &AnyValue{"carol"}, got.(Value)))
+
+	// The file is only read the first time.
+	assert.NoError(os.WriteFile(name, []byte("carol\n"), 0644))
+	again, err := lines(nil, file)
+	assert.NoError(err)
+	assert.Same(got, again)
+
+	_, err = lines(nil, []Value{NewStringValue(`"` + name + `.missing"`)})
+	assert.Error(err)
+}
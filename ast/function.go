@@ -0,0 +1,156 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jacobsimpson/jt/datetime"
+)
+
+// maxCallDepth limits how deeply functions can call each other, so a
+// recursive function that never stops is an error, rather than a crash.
+const maxCallDepth = 10000
+
+// Function is a function defined by a program. It is called the same way as a
+// builtin, `f(%1)` or `%1.f()`, and its value is the value of its body.
+//
+//	function trimPeriod(s string) { s.rtrim(".") }
+type Function struct {
+	Name       string
+	Parameters []*Parameter
+	Body       *Block
+}
+
+// Parameter is a parameter of a Function. A parameter with a Type only
+// accepts values of that type. A value from the input that hasn't got a type
+// yet is converted to the type, the same way the conversion functions convert
+// it.
+type Parameter struct {
+	Name string
+	Type string
+}
+
+// ParameterTypes are the names of the types a parameter can be declared
+// with, and the checks for each of them.
+var ParameterTypes = map[string]func(Value) (Value, error){
+	"any":      func(v Value) (Value, error) { return v, nil },
+	"bool":     toBoolean,
+	"date":     typed(func(v Value) bool { _, ok := v.(*DateTimeValue); return ok }, convertDate),
+	"decimal":  typed(func(v Value) bool { _, ok := v.(*DoubleValue); return ok }, convertDecimal),
+	"duration": toDuration,
+	"int":      typed(func(v Value) bool { _, ok := v.(*IntegerValue); return ok }, convertInt),
+	"list":     toList,
+	"map":      only("map", func(v Value) bool { _, ok := v.(*MapValue); return ok }),
+	"regex":    typed(func(v Value) bool { _, ok := v.(*RegexpValue); return ok }, convertRegex),
+	"set":      only("set", func(v Value) bool { _, ok := v.(*SetValue); return ok }),
+	"string":   typed(func(v Value) bool { _, ok := v.(*StringValue); return ok }, convertString),
+	"tuple":    only("tuple", func(v Value) bool { _, ok := v.(*TupleValue); return ok }),
+}
+
+// Call calls the function with the values of its parameters. The body is
+// evaluated in a scope of its own, so the parameters, and any variable that is
+// assigned in the body, are local to the call.
+func (f *Function) Call(environment *Environment, parameters []Value) (interface{}, error) {
+	if len(parameters) != len(f.Parameters) {
+		return nil, fmt.Errorf("takes %d parameters, got %d", len(f.Parameters), len(parameters))
+	}
+	if environment.depth >= maxCallDepth {
+		return nil, &callDepthError{f.Name}
+	}
+	scope := environment.NewScope()
+	for i, p := range f.Parameters {
+		v := parameters[i]
+		if p.Type != "" && !isNull(v) {
+			var err error
+			if v, err = ParameterTypes[p.Type](v); err != nil {
+				return nil, fmt.Errorf("parameter %s (%s): %v", p.Name, p.Type, err)
+			}
+		}
+		scope.Variables[p.Name] = v
+	}
+	return f.Body.Evaluate(scope)
+}
+
+// callDepthError is the error for a call that is too deep. It is returned as
+// it is by every call it passes through, rather than being wrapped thousands of
+// times.
+type callDepthError struct {
+	name string
+}
+
+func (e *callDepthError) Error() string {
+	return fmt.Sprintf("%s: functions called each other more than %d times", e.name, maxCallDepth)
+}
+
+func (f *Function) String() string {
+	parameters := []string{}
+	for _, p := range f.Parameters {
+		parameters = append(parameters, strings.TrimSpace(p.Name+" "+p.Type))
+	}
+	return fmt.Sprintf("function %s(%s) %s", f.Name, strings.Join(parameters, ", "), f.Body)
+}
+
+// typed makes the check for a type. A value of the type is accepted as it is,
+// and a value from the input is converted with one of the conversion
+// functions.
+func typed(is func(Value) bool, convert Builtin) func(Value) (Value, error) {
+	return func(v Value) (Value, error) {
+		if is(v) {
+			return v, nil
+		}
+		if _, ok := v.(*AnyValue); !ok {
+			return nil, fmt.Errorf("%s is the wrong type", v)
+		}
+		c, err := convert(nil, []Value{v})
+		if err != nil {
+			return nil, err
+		}
+		return c.(Value), nil
+	}
+}
+
+// only makes the check for a type that a value from the input is never
+// converted to.
+func only(name string, is func(Value) bool) func(Value) (Value, error) {
+	return func(v Value) (Value, error) {
+		if is(v) {
+			return v, nil
+		}
+		return nil, fmt.Errorf("%s is not a %s", v, name)
+	}
+}
+
+func toBoolean(v Value) (Value, error) {
+	switch t := v.(type) {
+	case *BooleanValue:
+		return t, nil
+	case *AnyValue:
+		b, err := parseBool(t.raw)
+		if err != nil {
+			return nil, err
+		}
+		return NewBooleanValue(b), nil
+	}
+	return nil, fmt.Errorf("%s is not a boolean", v)
+}
+
+func toDuration(v Value) (Value, error) {
+	switch t := v.(type) {
+	case *DurationValue:
+		return t, nil
+	case *AnyValue:
+		d, err := datetime.ParseDuration(t.raw)
+		if err != nil {
+			return nil, err
+		}
+		return &DurationValue{raw: t.raw, value: d}, nil
+	}
+	return nil, fmt.Errorf("%s is not a duration", v)
+}
+
+func toList(v Value) (Value, error) {
+	if l, ok := v.(*ListValue); ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("%s is not a list", v)
+}
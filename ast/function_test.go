@@ -0,0 +1,125 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionCall(t *testing.T) {
+	environment := NewEnvironment()
+	environment.Row = &Row{LineNumber: 1, Columns: []string{"a 3", "a", "3"}}
+	environment.Set("global", &AnyValue{"g"})
+	environment.Functions = map[string]*Function{
+		"double": {
+			Name:       "double",
+			Parameters: []*Parameter{{Name: "n", Type: "int"}},
+			Body: &Block{Statements: []Expression{
+				&Arithmetic{NewVarValue("n"), MUL_Operator, &IntegerValue{"2", 2}},
+			}},
+		},
+		"local": {
+			Name:       "local",
+			Parameters: []*Parameter{{Name: "s"}},
+			Body: &Block{Statements: []Expression{
+				&Assignment{Name: "global", Value: NewVarValue("s")},
+				NewVarValue("global"),
+			}},
+		},
+		"orDefault": {
+			Name:       "orDefault",
+			Parameters: []*Parameter{{Name: "x"}},
+			Body: &Block{Statements: []Expression{
+				&Coalesce{NewVarValue("x"), NewStringValue(`"d"`)},
+			}},
+		},
+		"show": {
+			Name:       "show",
+			Parameters: []*Parameter{{Name: "x"}},
+			Body: &Block{Statements: []Expression{
+				NewStringValue(`"called"`),
+			}},
+		},
+		"loop": {
+			Name:       "loop",
+			Parameters: []*Parameter{{Name: "n"}},
+			Body: &Block{Statements: []Expression{
+				&Command{Name: "loop", Parameters: []Expression{NewVarValue("n")}},
+			}},
+		},
+	}
+	call := func(name string, parameters ...Expression) Expression {
+		return &Command{Name: name, Parameters: parameters}
+	}
+
+	tests := []struct {
+		name       string
+		expression Expression
+		want       interface{}
+	}{
+		{"typed parameter", call("double", NewVarValue("%2")), &IntegerValue{"6", 6}},
+		{"method call result", call("upper", call("local", NewVarValue("%1"))), &AnyValue{"A"}},
+		{"null", call("double", NewVarValue("%3")), NewNullValue()},
+		{"null handled by the body", call("orDefault", NewVarValue("%9")), NewStringValue(`"d"`)},
+		{"called with null", call("show", NewVarValue("%9")), NewStringValue(`"called"`)},
+		{"global", NewVarValue("global"), &AnyValue{"g"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := evaluateValue(environment, test.expression)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+
+	errors := []struct {
+		name       string
+		expression Expression
+	}{
+		{"wrong type", call("double", NewStringValue(`"3"`))},
+		{"not convertible", call("double", NewVarValue("%1"))},
+		{"too many parameters", call("double", NewVarValue("%2"), NewVarValue("%2"))},
+		{"recursion", call("loop", NewVarValue("%2"))},
+	}
+
+	for _, test := range errors {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.expression.Evaluate(environment)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParameterTypes(t *testing.T) {
+	tests := []struct {
+		parameterType string
+		value         Value
+		want          Value
+	}{
+		{"any", &AnyValue{"x"}, &AnyValue{"x"}},
+		{"bool", &AnyValue{"true"}, NewBooleanValue(true)},
+		{"decimal", &AnyValue{"1.5"}, mustDouble(t, "1.5")},
+		{"duration", mustDuration(t, "2h"), mustDuration(t, "2h")},
+		{"int", &AnyValue{"0x10"}, &IntegerValue{"0x10", 16}},
+		{"list", NewListValue([]Value{}), NewListValue([]Value{})},
+		{"set", NewSetValue([]Value{}), NewSetValue([]Value{})},
+		{"tuple", NewTupleValue([]Value{&AnyValue{"x"}}), NewTupleValue([]Value{&AnyValue{"x"}})},
+		{"string", &AnyValue{"x"}, &StringValue{raw: `"x"`, value: "x"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.parameterType, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := ParameterTypes[test.parameterType](test.value)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InterpolatedString is a double quoted string with columns or expressions
+// embedded in it. The parts are evaluated and joined together each time the
+// string is evaluated.
+//
+//	"%1 --> %2 %3[:-2]"
+//	"total: ${%2 * %3}"
+type InterpolatedString struct {
+	Parts []Expression
+}
+
+func (s *InterpolatedString) Evaluate(environment *Environment) (interface{}, error) {
+	var b strings.Builder
+	for _, p := range s.Parts {
+		v, err := p.Evaluate(environment)
+		if err != nil {
+			return nil, fmt.Errorf("could not evaluate %s: %w", p, err)
+		}
+		fmt.Fprintf(&b, "%v", printable(v))
+	}
+	return b.String(), nil
+}
+
+func (s *InterpolatedString) String() string {
+	var b strings.Builder
+	b.WriteString(`"`)
+	for _, p := range s.Parts {
+		switch t := p.(type) {
+		case *StringValue:
+			b.WriteString(t.raw[1 : len(t.raw)-1])
+		case *VarValue, *RangeExpression:
+			b.WriteString(t.String())
+		default:
+			fmt.Fprintf(&b, "${%s}", t)
+		}
+	}
+	b.WriteString(`"`)
+	return b.String()
+}
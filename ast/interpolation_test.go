@@ -0,0 +1,65 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolatedString(t *testing.T) {
+	intp := func(i int) *int { return &i }
+	tests := []struct {
+		s    *InterpolatedString
+		want string
+	}{
+		{
+			&InterpolatedString{Parts: []Expression{
+				NewVarValue("%1"),
+				NewStringValue(`" --> "`),
+				NewVarValue("%2"),
+				NewStringValue(`" "`),
+				&RangeExpression{Expression: NewVarValue("%3"), End: intp(-2)},
+			}},
+			"a.txt --> bob 123",
+		},
+		{
+			&InterpolatedString{Parts: []Expression{
+				NewStringValue(`"line "`),
+				NewVarValue("%#"),
+				NewStringValue(`": "`),
+				&Command{Name: "upper", Parameters: []Expression{NewVarValue("%2")}},
+			}},
+			"line 7: BOB",
+		},
+		{
+			&InterpolatedString{Parts: []Expression{
+				&Arithmetic{Left: NewVarValue("%3"), Operator: ADD_Operator, Right: NewIntegerValue("1", 1)},
+				NewVarValue("%4"),
+			}},
+			"12346",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			environment := NewEnvironment()
+			environment.Row = &Row{LineNumber: 7, Columns: []string{"a.txt bob 12345", "a.txt", "bob", "12345"}}
+
+			got, err := test.s.Evaluate(environment)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
+
+func TestInterpolatedStringString(t *testing.T) {
+	s := &InterpolatedString{Parts: []Expression{
+		NewVarValue("%1"),
+		NewStringValue(`" is "`),
+		&Command{Name: "upper", Parameters: []Expression{NewVarValue("%2")}},
+	}}
+
+	assert.Equal(t, `"%1 is ${upper(%2)}"`, s.String())
+}
@@ -0,0 +1,68 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// IntervalValue is a Value implementation to hold a range of values, from a
+// start to an end, including both. A value is in the range when it is >= the
+// start and <= the end, by the usual comparison rules, so a date at the end of
+// a range includes the whole of the period it covers.
+//
+//	10..20
+//	2024-01-01T..2024-01-31T
+type IntervalValue struct {
+	start Value
+	end   Value
+}
+
+func NewIntervalValue(start, end Value) Value {
+	return &IntervalValue{start, end}
+}
+
+// contains reports whether a value is within the range.
+func (v *IntervalValue) contains(value Value) bool {
+	return ge(nil, value, v.start) && le(nil, value, v.end)
+}
+
+func (v *IntervalValue) Raw() string {
+	return v.String()
+}
+
+func (v *IntervalValue) Value() interface{} {
+	return []Value{v.start, v.end}
+}
+
+func (v *IntervalValue) String() string {
+	return fmt.Sprintf("%s..%s", v.start, v.end)
+}
+
+func (v *IntervalValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v, nil
+}
+
+// IntervalLiteral is a range written in a program, with a start or an end
+// that has to be evaluated each time, like `%2..%3`.
+type IntervalLiteral struct {
+	Start Expression
+	End   Expression
+}
+
+func NewIntervalLiteral(start, end Expression) Expression {
+	if values, ok := constant([]Expression{start, end}); ok {
+		return NewIntervalValue(values[0], values[1])
+	}
+	return &IntervalLiteral{start, end}
+}
+
+func (e *IntervalLiteral) Evaluate(environment *Environment) (interface{}, error) {
+	values, err := evaluateAll(environment, []Expression{e.Start, e.End})
+	if err != nil {
+		return nil, err
+	}
+	return NewIntervalValue(values[0], values[1]), nil
+}
+
+func (e *IntervalLiteral) String() string {
+	return fmt.Sprintf("%s..%s", e.Start, e.End)
+}
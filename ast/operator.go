@@ -9,6 +9,14 @@ const (
 	NE_Operator
 	GE_Operator
 	GT_Operator
+	IN_Operator
+	ADD_Operator
+	SUB_Operator
+	MUL_Operator
+	DIV_Operator
+	INTDIV_Operator
+	MOD_Operator
+	POW_Operator
 )
 
 func (o Operator) String() string {
@@ -25,6 +33,22 @@ func (o Operator) String() string {
 		return ">="
 	case GT_Operator:
 		return ">"
+	case IN_Operator:
+		return "in"
+	case ADD_Operator:
+		return "+"
+	case SUB_Operator:
+		return "-"
+	case MUL_Operator:
+		return "*"
+	case DIV_Operator:
+		return "/"
+	case INTDIV_Operator:
+		return "\\"
+	case MOD_Operator:
+		return "%"
+	case POW_Operator:
+		return "^"
 	}
 	return "Unknown operator"
 }
@@ -2,17 +2,39 @@ package ast
 
 import (
 	"fmt"
+	"sort"
 )
 
+// Program is a complete jt script. The Begin blocks are executed before any
+// input is read, the Rules are applied to every line of input, and the End
+// blocks are executed after all the input has been read. The Functions can be
+// called from any of them.
 type Program struct {
-	Rules []*Rule
+	Functions map[string]*Function
+	Begin     []*Block
+	Rules     []*Rule
+	End       []*Block
 }
 
 func (p *Program) String() string {
 	result := "Program [\n"
+	names := []string{}
+	for name := range p.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result += fmt.Sprintf("    %s\n", p.Functions[name])
+	}
+	for _, b := range p.Begin {
+		result += fmt.Sprintf("    BEGIN %s\n", b.String())
+	}
 	for _, r := range p.Rules {
 		result += fmt.Sprintf("    %s\n", r.String())
 	}
+	for _, b := range p.End {
+		result += fmt.Sprintf("    END %s\n", b.String())
+	}
 	result += "]"
 	return result
 }
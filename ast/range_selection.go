@@ -0,0 +1,50 @@
+package ast
+
+import (
+	"fmt"
+)
+
+// RangeSelection selects every line from a line that matches Start, up to a
+// line that matches End. It then waits for Start to match again. The lines
+// that match Start and End are part of the range, unless the range is
+// Exclusive.
+//
+// The RangeSelection remembers whether it is inside a range, so each
+// RangeSelection must only be used by one rule.
+type RangeSelection struct {
+	Start     Expression
+	End       Expression
+	Exclusive bool
+	active    bool
+}
+
+func (r *RangeSelection) Evaluate(environment *Environment) (interface{}, error) {
+	if !r.active {
+		if ok, err := isTrue(environment, r.Start); !ok {
+			return false, err
+		}
+		r.active = true
+		if r.Exclusive {
+			return false, nil
+		}
+		// An inclusive range can start and end on the same line.
+	}
+
+	ok, err := isTrue(environment, r.End)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		r.active = false
+		return !r.Exclusive, nil
+	}
+	return true, nil
+}
+
+func (r *RangeSelection) String() string {
+	operator := "->"
+	if r.Exclusive {
+		operator = "~>"
+	}
+	return fmt.Sprintf("%s %s %s", r.Start, operator, r.End)
+}
@@ -0,0 +1,46 @@
+package ast
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeSelection(t *testing.T) {
+	lines := []string{"header", "start", "one", "end", "footer", "start end", "two"}
+
+	tests := []struct {
+		exclusive bool
+		want      []bool
+	}{
+		{false, []bool{false, true, true, true, false, true, false}},
+		{true, []bool{false, false, true, false, false, false, true}},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("exclusive %v", test.exclusive), func(t *testing.T) {
+			assert := assert.New(t)
+
+			start, err := NewRegexpValue("start")
+			assert.NoError(err)
+			end, err := NewRegexpValue("end")
+			assert.NoError(err)
+			selection := &RangeSelection{
+				Start:     &Comparison{NewVarValue("%0"), EQ_Operator, start},
+				End:       &Comparison{NewVarValue("%0"), EQ_Operator, end},
+				Exclusive: test.exclusive,
+			}
+
+			got := []bool{}
+			for i, line := range lines {
+				environment := &Environment{Row: &Row{LineNumber: i + 1, Columns: []string{line, line}}}
+				selected, err := selection.Evaluate(environment)
+				assert.NoError(err)
+				got = append(got, selected.(bool))
+			}
+
+			assert.Equal(test.want, got)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package ast
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// The regexp to use for splitting input lines into columns.
+var defaultSplit = regexp.MustCompile("[[:blank:]]+")
+
+// Row is the current line of input. Columns[0] is the whole line, and the
+// rest of the Columns are the columns of the line. Separators[i] is the
+// whitespace in front of column i, which is the leading whitespace of the line
+// for column 1, so the line can be put back together when a column is
+// changed. A Row without Separators has a single space between its columns.
+type Row struct {
+	LineNumber int
+	Columns    []string
+	Separators []string
+}
+
+// NewRow splits a line into columns.
+func NewRow(lineNumber int, line string) *Row {
+	row := &Row{
+		LineNumber: lineNumber,
+		Columns:    []string{line},
+		Separators: []string{""},
+	}
+	if line == "" {
+		return row
+	}
+	start, separator := 0, ""
+	for _, m := range defaultSplit.FindAllStringIndex(line, -1) {
+		if m[0] > 0 {
+			row.Columns = append(row.Columns, line[start:m[0]])
+			row.Separators = append(row.Separators, separator)
+		}
+		start, separator = m[1], line[m[0]:m[1]]
+	}
+	row.Columns = append(row.Columns, line[start:])
+	row.Separators = append(row.Separators, separator)
+	return row
+}
+
+// Set changes the text of a column, and puts the whole line back together.
+// Negative columns count back from the last column. A column past the end of
+// the line is appended, with empty columns in between. Setting column 0
+// replaces the whole line, which is split into columns again.
+func (r *Row) Set(column int, text string) error {
+	if column == 0 {
+		*r = *NewRow(r.LineNumber, text)
+		return nil
+	}
+	i, ok := r.index(column)
+	if !ok {
+		return fmt.Errorf("column %d is not in the line", column)
+	}
+	for len(r.Columns) < i {
+		r.Append("")
+	}
+	if i == len(r.Columns) {
+		r.Append(text)
+		return nil
+	}
+	r.realign(i, text)
+	r.Columns[i] = text
+	r.rebuild()
+	return nil
+}
+
+// Append adds a column to the end of the line. It is separated from the last
+// column by a tab, if the last column is separated by a tab, otherwise by a
+// space.
+func (r *Row) Append(text string) {
+	r.separators()
+	separator := ""
+	if n := len(r.Columns); n > 1 {
+		separator = " "
+		if n > 2 && strings.Trim(r.Separators[n-1], "\t") == "" {
+			separator = "\t"
+		}
+	}
+	r.Columns = append(r.Columns, text)
+	r.Separators = append(r.Separators, separator)
+	r.rebuild()
+}
+
+// Delete removes a column, and the whitespace in front of it. Deleting a
+// column that isn't in the line does nothing.
+func (r *Row) Delete(column int) error {
+	if column == 0 {
+		return fmt.Errorf("the whole line can not be deleted")
+	}
+	i, ok := r.index(column)
+	if !ok || i >= len(r.Columns) {
+		return nil
+	}
+	r.separators()
+	r.Columns = append(r.Columns[:i], r.Columns[i+1:]...)
+	// The leading whitespace of the line stays, so if the first column is
+	// deleted, it is the whitespace in front of the second column that goes.
+	if i == 1 && len(r.Separators) > 2 {
+		i = 2
+	}
+	r.Separators = append(r.Separators[:i], r.Separators[i+1:]...)
+	r.rebuild()
+	return nil
+}
+
+// index converts a column number into an index of Columns. A negative
+// column, counting back from the last column, that is before the first column
+// isn't in the line.
+func (r *Row) index(column int) (int, bool) {
+	if column < 0 {
+		column += len(r.Columns)
+		if column < 1 {
+			return 0, false
+		}
+	}
+	return column, true
+}
+
+// realign keeps the columns after a changed column lined up with the same
+// columns of the lines around it, as far as the whitespace allows. Numbers are
+// usually lined up on the right, like the sizes of `ls -l`, so the space in
+// front of a number shrinks or grows. Text is usually lined up on the left, so
+// the space after it does. Only separators made of spaces are changed, and a
+// space is always left between two columns.
+func (r *Row) realign(i int, text string) {
+	r.separators()
+	s := i + 1
+	if isNumber(r.Columns[i]) || isNumber(text) {
+		s = i
+	}
+	if s >= len(r.Separators) {
+		return
+	}
+	separator := r.Separators[s]
+	if separator == "" || strings.Trim(separator, " ") != "" {
+		return
+	}
+	width := len(separator) + utf8.RuneCountInString(r.Columns[i]) - utf8.RuneCountInString(text)
+	if width < 1 && s > 1 {
+		width = 1
+	} else if width < 0 {
+		width = 0
+	}
+	r.Separators[s] = strings.Repeat(" ", width)
+}
+
+func isNumber(s string) bool {
+	_, err := parseDecimal(s)
+	return err == nil
+}
+
+// separators fills in the Separators of a Row that was made without them.
+func (r *Row) separators() {
+	if r.Separators != nil {
+		return
+	}
+	r.Separators = []string{""}
+	for i := 1; i < len(r.Columns); i++ {
+		if i == 1 {
+			r.Separators = append(r.Separators, "")
+		} else {
+			r.Separators = append(r.Separators, " ")
+		}
+	}
+}
+
+// rebuild puts the columns back together into the whole line.
+func (r *Row) rebuild() {
+	r.separators()
+	var b strings.Builder
+	for i := 1; i < len(r.Columns); i++ {
+		b.WriteString(r.Separators[i])
+		b.WriteString(r.Columns[i])
+	}
+	r.Columns[0] = b.String()
+}
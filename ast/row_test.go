@@ -0,0 +1,81 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRow(t *testing.T) {
+	tests := []struct {
+		line       string
+		columns    []string
+		separators []string
+	}{
+		{"", []string{""}, []string{""}},
+		{"a b", []string{"a b", "a", "b"}, []string{"", "", " "}},
+		{"  a \tb", []string{"  a \tb", "a", "b"}, []string{"", "  ", " \t"}},
+		{"a b ", []string{"a b ", "a", "b", ""}, []string{"", "", " ", " "}},
+		{"   ", []string{"   ", ""}, []string{"", "   "}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got := NewRow(3, test.line)
+
+			assert.Equal(3, got.LineNumber)
+			assert.Equal(test.columns, got.Columns)
+			assert.Equal(test.separators, got.Separators)
+		})
+	}
+}
+
+func TestRowChanges(t *testing.T) {
+	ls := "-rw-r--r--  1 jacob  staff    1234 Jan  1 a.txt"
+
+	tests := []struct {
+		name   string
+		line   string
+		change func(*Row) error
+		want   string
+	}{
+		{"wider number", ls, func(r *Row) error { return r.Set(5, "123456") }, "-rw-r--r--  1 jacob  staff  123456 Jan  1 a.txt"},
+		{"narrower number", ls, func(r *Row) error { return r.Set(5, "9") }, "-rw-r--r--  1 jacob  staff       9 Jan  1 a.txt"},
+		{"number too wide", ls, func(r *Row) error { return r.Set(5, "123456789") }, "-rw-r--r--  1 jacob  staff 123456789 Jan  1 a.txt"},
+		{"wider text", ls, func(r *Row) error { return r.Set(3, "jacobs") }, "-rw-r--r--  1 jacobs staff    1234 Jan  1 a.txt"},
+		{"narrower text", ls, func(r *Row) error { return r.Set(-6, "jo") }, "-rw-r--r--  1 jo     staff    1234 Jan  1 a.txt"},
+		{"last column", ls, func(r *Row) error { return r.Set(-1, "b.txt.gz") }, "-rw-r--r--  1 jacob  staff    1234 Jan  1 b.txt.gz"},
+		{"tabs", "a\tb\tc", func(r *Row) error { return r.Set(1, "aaa") }, "aaa\tb\tc"},
+		{"leading whitespace", "  1 b", func(r *Row) error { return r.Set(1, "100") }, "100 b"},
+		{"past the end", "a b", func(r *Row) error { return r.Set(4, "d") }, "a b  d"},
+		{"whole line", "a b", func(r *Row) error { return r.Set(0, "x  y") }, "x  y"},
+		{"empty line", "", func(r *Row) error { return r.Set(1, "a") }, "a"},
+		{"append", "a  b", func(r *Row) error { r.Append("c"); return nil }, "a  b c"},
+		{"append tab", "a\tb", func(r *Row) error { r.Append("c"); return nil }, "a\tb\tc"},
+		{"delete", "a  b c", func(r *Row) error { return r.Delete(2) }, "a c"},
+		{"delete first", " a  b c", func(r *Row) error { return r.Delete(1) }, " b c"},
+		{"delete last", "a b c", func(r *Row) error { return r.Delete(-1) }, "a b"},
+		{"delete missing", "a b", func(r *Row) error { return r.Delete(5) }, "a b"},
+		{"without separators", "", func(r *Row) error {
+			*r = Row{LineNumber: 1, Columns: []string{"a b", "a", "b"}}
+			return r.Set(2, "c")
+		}, "a c"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			row := NewRow(1, test.line)
+
+			err := test.change(row)
+
+			assert.NoError(err)
+			assert.Equal(test.want, row.Columns[0])
+		})
+	}
+
+	assert.Error(t, NewRow(1, "a b").Set(-3, "x"))
+	assert.Error(t, NewRow(1, "a b").Delete(0))
+}
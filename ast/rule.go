@@ -4,13 +4,19 @@ import (
 	"fmt"
 )
 
+// Rule is a selection and the block to execute for each line the selection
+// matches. A nil Selection matches every line, any other selection matches the
+// lines it is true for.
 type Rule struct {
 	Selection Expression
 	Block     *Block
 }
 
 func (r *Rule) Evaluate(environment *Environment) (interface{}, error) {
-	return r.Selection.Evaluate(environment)
+	if r.Selection == nil {
+		return true, nil
+	}
+	return isTrue(environment, r.Selection)
 }
 
 func (r *Rule) Execute(environment *Environment) error {
@@ -0,0 +1,229 @@
+package ast
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// The string functions work on string literals and on values from the input.
+// A string that comes from the input still doesn't have a type once it has
+// been changed, so `%1.trim() > 5` compares numbers, just like `%1 > 5`.
+
+func lower(environment *Environment, parameters []Value) (interface{}, error) {
+	return mapText(parameters, strings.ToLower)
+}
+
+func upper(environment *Environment, parameters []Value) (interface{}, error) {
+	return mapText(parameters, strings.ToUpper)
+}
+
+// title capitalizes the first letter of each word.
+func title(environment *Environment, parameters []Value) (interface{}, error) {
+	return mapText(parameters, strings.Title)
+}
+
+// reverse reverses the characters of a string, rather than the bytes, so
+// multi-byte characters stay intact.
+func reverse(environment *Environment, parameters []Value) (interface{}, error) {
+	return mapText(parameters, func(s string) string {
+		r := []rune(s)
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	})
+}
+
+// trim removes whitespace from both ends of a string, or, with a second
+// parameter, any of the characters in the second parameter.
+//
+//	%1.trim()
+//	%1.trim(".,")
+func trim(environment *Environment, parameters []Value) (interface{}, error) {
+	return trimText(parameters, strings.TrimSpace, strings.Trim)
+}
+
+func ltrim(environment *Environment, parameters []Value) (interface{}, error) {
+	return trimText(parameters,
+		func(s string) string { return strings.TrimLeftFunc(s, unicode.IsSpace) },
+		strings.TrimLeft)
+}
+
+func rtrim(environment *Environment, parameters []Value) (interface{}, error) {
+	return trimText(parameters,
+		func(s string) string { return strings.TrimRightFunc(s, unicode.IsSpace) },
+		strings.TrimRight)
+}
+
+func startsWith(environment *Environment, parameters []Value) (interface{}, error) {
+	return testText(parameters, strings.HasPrefix)
+}
+
+func endsWith(environment *Environment, parameters []Value) (interface{}, error) {
+	return testText(parameters, strings.HasSuffix)
+}
+
+// contains is true if a string contains another string, or a match for a
+// regular expression.
+//
+//	%2.contains("error")
+//	%2.contains(/err(or)?/)
+func contains(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 2, 2); err != nil {
+		return nil, err
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	if re, ok := parameters[1].(*RegexpValue); ok {
+		return re.re.MatchString(s), nil
+	}
+	substring, err := toText(parameters[1])
+	if err != nil {
+		return nil, err
+	}
+	return strings.Contains(s, substring), nil
+}
+
+// replace replaces every occurrence of a string, or every match of a regular
+// expression. A replacement for a regular expression can refer to the groups
+// of the match, $1 for the first group.
+//
+//	%1.replace("-", "_")
+//	%1.replace(/(\d+)-(\d+)/, "$2-$1")
+func replace(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 3, 3); err != nil {
+		return nil, err
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	replacement, err := toText(parameters[2])
+	if err != nil {
+		return nil, err
+	}
+	if re, ok := parameters[1].(*RegexpValue); ok {
+		return textLike(parameters[0], re.re.ReplaceAllString(s, replacement)), nil
+	}
+	old, err := toText(parameters[1])
+	if err != nil {
+		return nil, err
+	}
+	return textLike(parameters[0], strings.ReplaceAll(s, old, replacement)), nil
+}
+
+// split breaks a string into a list at each separator, which can be a string
+// or a regular expression. Without a separator the string is split at runs of
+// whitespace.
+//
+//	$PATH.split(":")
+func split(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 2); err != nil {
+		return nil, err
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	var parts []string
+	if len(parameters) == 1 {
+		parts = strings.Fields(s)
+	} else if re, ok := parameters[1].(*RegexpValue); ok {
+		parts = re.re.Split(s, -1)
+	} else {
+		separator, err := toText(parameters[1])
+		if err != nil {
+			return nil, err
+		}
+		parts = strings.Split(s, separator)
+	}
+	values := []Value{}
+	for _, p := range parts {
+		values = append(values, textLike(parameters[0], p))
+	}
+	return NewListValue(values), nil
+}
+
+// join joins the values of a list into a string, with a separator between
+// each of them.
+//
+//	$PATH.split(":").join(" ")
+func join(environment *Environment, parameters []Value) (interface{}, error) {
+	if err := parameterCount(parameters, 2, 2); err != nil {
+		return nil, err
+	}
+	list, ok := parameters[0].(*ListValue)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a list", parameters[0])
+	}
+	separator, err := toText(parameters[1])
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{}
+	for _, v := range list.values {
+		parts = append(parts, v.String())
+	}
+	return toValue(strings.Join(parts, separator))
+}
+
+// mapText applies f to the only parameter.
+func mapText(parameters []Value, f func(string) string) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 1); err != nil {
+		return nil, err
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	return textLike(parameters[0], f(s)), nil
+}
+
+// trimText trims with space when there is only one parameter, and with cut
+// when the characters to trim are given.
+func trimText(parameters []Value, space func(string) string, cut func(string, string) string) (interface{}, error) {
+	if err := parameterCount(parameters, 1, 2); err != nil {
+		return nil, err
+	}
+	if len(parameters) == 1 {
+		return mapText(parameters, space)
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	cutset, err := toText(parameters[1])
+	if err != nil {
+		return nil, err
+	}
+	return textLike(parameters[0], cut(s, cutset)), nil
+}
+
+// testText applies the test f to the two parameters.
+func testText(parameters []Value, f func(string, string) bool) (interface{}, error) {
+	if err := parameterCount(parameters, 2, 2); err != nil {
+		return nil, err
+	}
+	s, err := toText(parameters[0])
+	if err != nil {
+		return nil, err
+	}
+	t, err := toText(parameters[1])
+	if err != nil {
+		return nil, err
+	}
+	return f(s, t), nil
+}
+
+// textLike makes a value for s that has the same type as v, so a value from
+// the input stays typeless.
+func textLike(v Value, s string) Value {
+	if _, ok := v.(*AnyValue); ok {
+		return &AnyValue{s}
+	}
+	return &StringValue{raw: strconv.Quote(s), value: s}
+}
@@ -0,0 +1,80 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringFunctions(t *testing.T) {
+	re := func(s string) Value {
+		r, _ := NewRegexpValue(s)
+		return r
+	}
+	str := func(s string) Value { return NewStringValue(`"` + s + `"`) }
+	any := func(s string) Value { return &AnyValue{s} }
+	list := func(values ...Value) Value { return NewListValue(values) }
+
+	tests := []struct {
+		name       string
+		function   Builtin
+		parameters []Value
+		want       interface{}
+	}{
+		{"lower", lower, []Value{any("MiXeD")}, any("mixed")},
+		{"upper", upper, []Value{str("MiXeD")}, &StringValue{raw: `"MIXED"`, value: "MIXED"}},
+		{"title", title, []Value{any("the end")}, any("The End")},
+		{"reverse", reverse, []Value{any("日本語!")}, any("!語本日")},
+		{"trim", trim, []Value{any(" \tabc  ")}, any("abc")},
+		{"trim cutset", trim, []Value{any("..abc.,"), str(".,")}, any("abc")},
+		{"ltrim", ltrim, []Value{any("  abc  ")}, any("abc  ")},
+		{"ltrim cutset", ltrim, []Value{any("00120"), str("0")}, any("120")},
+		{"rtrim", rtrim, []Value{any("  abc  ")}, any("  abc")},
+		{"rtrim cutset", rtrim, []Value{any("1.500"), str("0")}, any("1.5")},
+		{"startswith", startsWith, []Value{any("abc"), str("ab")}, true},
+		{"startswith", startsWith, []Value{any("abc"), str("bc")}, false},
+		{"endswith", endsWith, []Value{any("abc"), str("bc")}, true},
+		{"endswith", endsWith, []Value{any("abc"), str("ab")}, false},
+		{"contains", contains, []Value{any("abc"), str("b")}, true},
+		{"contains", contains, []Value{any("abc"), str("d")}, false},
+		{"contains regex", contains, []Value{any("abc"), re("^a.c$")}, true},
+		{"replace", replace, []Value{any("a-b-c"), str("-"), str("_")}, any("a_b_c")},
+		{"replace regex", replace, []Value{any("12-34"), re(`(\d+)-(\d+)`), str("$2-$1")}, any("34-12")},
+		{"split", split, []Value{any("a:b::c"), str(":")}, list(any("a"), any("b"), any(""), any("c"))},
+		{"split regex", split, []Value{any("a1b22c"), re("[0-9]+")}, list(any("a"), any("b"), any("c"))},
+		{"split whitespace", split, []Value{any("  a b\tc ")}, list(any("a"), any("b"), any("c"))},
+		{"join", join, []Value{list(any("a"), any("b")), str(", ")}, &StringValue{raw: `"a, b"`, value: "a, b"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := test.function(NewEnvironment(), test.parameters)
+
+			assert.NoError(err)
+			assert.Equal(test.want, got)
+		})
+	}
+}
+
+func TestStringFunctionErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		function   Builtin
+		parameters []Value
+	}{
+		{"too many parameters", lower, []Value{&AnyValue{"a"}, &AnyValue{"b"}}},
+		{"missing parameter", startsWith, []Value{&AnyValue{"a"}}},
+		{"not a string", upper, []Value{&IntegerValue{"1", 1}}},
+		{"not a list", join, []Value{&AnyValue{"a"}, NewStringValue(`","`)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.function(NewEnvironment(), test.parameters)
+
+			assert.Error(t, err)
+		})
+	}
+}
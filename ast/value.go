@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jacobsimpson/jt/datetime"
 	"github.com/shopspring/decimal"
@@ -18,6 +19,51 @@ type Value interface {
 	Evaluate(environment *Environment) (interface{}, error)
 }
 
+// evaluateValue evaluates an expression down to a Value. Variables and
+// keywords are resolved to the value they currently represent, other
+// expressions are evaluated and the result is wrapped in the matching Value
+// implementation.
+func evaluateValue(environment *Environment, expression Expression) (Value, error) {
+	switch e := expression.(type) {
+	case *VarValue, *KeywordValue:
+		if v, ok := resolveVar(environment, e).(Value); ok {
+			return v, nil
+		}
+	case Value:
+		return e, nil
+	}
+	v, err := expression.Evaluate(environment)
+	if err != nil {
+		return nil, err
+	}
+	return toValue(v)
+}
+
+// toValue wraps the result of evaluating an expression in a Value.
+func toValue(v interface{}) (Value, error) {
+	switch t := v.(type) {
+	case Value:
+		return t, nil
+	case string:
+		return &StringValue{raw: strconv.Quote(t), value: t}, nil
+	case int64:
+		return &IntegerValue{raw: strconv.FormatInt(t, 10), value: t}, nil
+	case *decimal.Decimal:
+		return &DoubleValue{raw: t.String(), value: t}, nil
+	case time.Time:
+		return newDateTimeValue(datetime.Period{Start: t}), nil
+	case datetime.Duration:
+		return &DurationValue{raw: t.String(), value: t}, nil
+	case *regexp.Regexp:
+		return &RegexpValue{raw: t.String(), re: t}, nil
+	case bool:
+		return NewBooleanValue(t), nil
+	case nil:
+		return NewNullValue(), nil
+	}
+	return nil, fmt.Errorf("%v can not be used as a value", v)
+}
+
 func NewVarValue(name string) Value {
 	return &VarValue{
 		name: name,
@@ -95,6 +141,45 @@ func NewStringValue(s string) Value {
 	}
 }
 
+// NewEscapedStringValue creates a new StringValue, given a string delimited at
+// each end by a double quote, replacing the escape sequences between the
+// quotes with the characters they represent. The escape sequences are the same
+// as in Go, `\t`, `\n`, `\"`, `\u00e9` and so on, along with `\%` and `\$`
+// for a `%` or `$` that isn't interpolated.
+func NewEscapedStringValue(s string) (Value, error) {
+	value, err := unescape(s[1 : len(s)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &StringValue{
+		raw:   s,
+		value: value,
+	}, nil
+}
+
+func unescape(s string) (string, error) {
+	var b strings.Builder
+	for len(s) > 0 {
+		if strings.HasPrefix(s, `\%`) || strings.HasPrefix(s, `\$`) {
+			b.WriteByte(s[1])
+			s = s[2:]
+			continue
+		}
+		r, multibyte, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return "", fmt.Errorf("invalid escape sequence at %s", s)
+		}
+		// A \x or octal escape is a single byte, rather than a character.
+		if r < utf8.RuneSelf || !multibyte {
+			b.WriteByte(byte(r))
+		} else {
+			b.WriteRune(r)
+		}
+		s = tail
+	}
+	return b.String(), nil
+}
+
 func (v *StringValue) Raw() string {
 	return v.raw
 }
@@ -111,23 +196,43 @@ func (v *StringValue) Evaluate(environment *Environment) (interface{}, error) {
 	return v.value, nil
 }
 
-// DateTimeValue is a Value implementation to hold a date/time.
+// DateTimeValue is a Value implementation to hold a date/time. The
+// granularity is the precision the date/time was written with, so a literal
+// like 2013T represents the whole of 2013. Dates and times that come from
+// anywhere else are instants.
 type DateTimeValue struct {
-	raw   string
-	value time.Time
+	raw         string
+	value       time.Time
+	granularity datetime.Granularity
 }
 
 func NewDateTimeValue(s string) (Value, error) {
-	date, err := datetime.ParseDateTime(datetime.LiteralFormats, s)
+	period, err := datetime.ParsePeriod(datetime.LiteralFormats, s)
 	if err != nil {
 		return nil, err
 	}
 	return &DateTimeValue{
-		raw:   s,
-		value: date,
+		raw:         s,
+		value:       period.Start,
+		granularity: period.Granularity,
 	}, nil
 }
 
+// newDateTimeValue makes a date/time that wasn't written as a literal, like
+// the result of date arithmetic. It is given the literal it would have been
+// written as.
+func newDateTimeValue(period datetime.Period) *DateTimeValue {
+	return &DateTimeValue{
+		raw:         period.String(),
+		value:       period.Start,
+		granularity: period.Granularity,
+	}
+}
+
+func (v *DateTimeValue) period() datetime.Period {
+	return datetime.Period{Start: v.value, Granularity: v.granularity}
+}
+
 func (v *DateTimeValue) Raw() string {
 	return v.raw
 }
@@ -137,10 +242,45 @@ func (v *DateTimeValue) Value() interface{} {
 }
 
 func (v *DateTimeValue) String() string {
-	return v.value.String()
+	return v.period().String()
 }
 
+// Evaluate is the date/time itself, rather than its time.Time, so that it
+// keeps its granularity, and prints as a literal.
 func (v *DateTimeValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v, nil
+}
+
+// DurationValue is a Value implementation to hold a length of time.
+type DurationValue struct {
+	raw   string
+	value datetime.Duration
+}
+
+func NewDurationValue(s string) (Value, error) {
+	d, err := datetime.ParseDuration(s)
+	if err != nil {
+		return nil, err
+	}
+	return &DurationValue{
+		raw:   s,
+		value: d,
+	}, nil
+}
+
+func (v *DurationValue) Raw() string {
+	return v.raw
+}
+
+func (v *DurationValue) Value() interface{} {
+	return v.value
+}
+
+func (v *DurationValue) String() string {
+	return v.value.String()
+}
+
+func (v *DurationValue) Evaluate(environment *Environment) (interface{}, error) {
 	return v.value, nil
 }
 
@@ -349,5 +489,111 @@ func (v *KeywordValue) String() string {
 }
 
 func (v *KeywordValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v.dateTime(), nil
+}
+
+// dateTime returns the date/time the keyword refers to at the moment. `now`
+// is the current instant, the other keywords are the whole of their day.
+func (v *KeywordValue) dateTime() *DateTimeValue {
+	t := time.Now()
+	switch v.value {
+	case "yesterday":
+		t = t.AddDate(0, 0, -1)
+	case "tomorrow":
+		t = t.AddDate(0, 0, 1)
+	}
+	if v.value == "now" {
+		return newDateTimeValue(datetime.Period{Start: t})
+	}
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	return newDateTimeValue(datetime.Period{Start: t, Granularity: datetime.Day})
+}
+
+// BooleanValue is a Value implementation to hold true or false.
+type BooleanValue struct {
+	value bool
+}
+
+func NewBooleanValue(value bool) Value {
+	return &BooleanValue{
+		value: value,
+	}
+}
+
+func (v *BooleanValue) Raw() string {
+	return v.String()
+}
+
+func (v *BooleanValue) Value() interface{} {
+	return v.value
+}
+
+func (v *BooleanValue) String() string {
+	return strconv.FormatBool(v.value)
+}
+
+func (v *BooleanValue) Evaluate(environment *Environment) (interface{}, error) {
 	return v.value, nil
 }
+
+// NullValue is a Value implementation for the absence of a value. A column
+// that isn't in the line, a variable that hasn't been assigned and an
+// environment variable that isn't set are all null.
+type NullValue struct{}
+
+func NewNullValue() Value {
+	return &NullValue{}
+}
+
+func (v *NullValue) Raw() string {
+	return "null"
+}
+
+func (v *NullValue) Value() interface{} {
+	return nil
+}
+
+func (v *NullValue) String() string {
+	return "null"
+}
+
+func (v *NullValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v, nil
+}
+
+// isNull reports whether a value is null.
+func isNull(v interface{}) bool {
+	_, ok := v.(*NullValue)
+	return ok
+}
+
+// ListValue is a Value implementation to hold a sequence of values.
+type ListValue struct {
+	values []Value
+}
+
+func NewListValue(values []Value) Value {
+	return &ListValue{
+		values: values,
+	}
+}
+
+func (v *ListValue) Raw() string {
+	return v.String()
+}
+
+func (v *ListValue) Value() interface{} {
+	return v.values
+}
+
+func (v *ListValue) String() string {
+	values := []string{}
+	for _, e := range v.values {
+		values = append(values, e.String())
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func (v *ListValue) Evaluate(environment *Environment) (interface{}, error) {
+	return v, nil
+}
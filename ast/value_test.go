@@ -15,6 +15,44 @@ func TestNewIntegerValueFromBinaryString(t *testing.T) {
 	assert.Equal(v, &IntegerValue{raw: "0b1000", value: 8})
 }
 
+func TestNewEscapedStringValue(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`"abc"`, "abc"},
+		{`"a\tb\nc"`, "a\tb\nc"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"it's"`, "it's"},
+		{`"caf\u00e9 \U0001F600"`, "café 😀"},
+		{`"\x41\101"`, "AA"},
+		{`"\xff"`, "\xff"},
+		{`"C:\\dir"`, `C:\dir`},
+		{`"\%1 \${x}"`, "%1 ${x}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			assert := assert.New(t)
+
+			v, err := NewEscapedStringValue(test.raw)
+
+			assert.NoError(err)
+			assert.Equal(&StringValue{raw: test.raw, value: test.want}, v)
+		})
+	}
+}
+
+func TestNewEscapedStringValueErrors(t *testing.T) {
+	for _, raw := range []string{`"\d"`, `"\u12"`, `"abc\"`} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := NewEscapedStringValue(raw)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
 func TestAllValuesAreExpressions(t *testing.T) {
 	assert := assert.New(t)
 
@@ -7,6 +7,44 @@ import (
 
 var Zero = time.Date(0, 0, 0, 0, 0, 0, 0, time.UTC)
 
+// Granularity is the precision a date/time was written with. A date/time
+// literal written to the day, like 2013-06-01T, represents the whole of that
+// day, not just the instant at midnight.
+type Granularity int
+
+// Granularities are ordered from the finest to the coarsest.
+const (
+	Instant Granularity = iota
+	Millisecond
+	Second
+	Minute
+	Hour
+	Day
+	Month
+	Year
+)
+
+// End returns the end of the period of this granularity that starts at t.
+func (g Granularity) End(t time.Time) time.Time {
+	switch g {
+	case Millisecond:
+		return t.Add(time.Millisecond)
+	case Second:
+		return t.Add(time.Second)
+	case Minute:
+		return t.Add(time.Minute)
+	case Hour:
+		return t.Add(time.Hour)
+	case Day:
+		return t.AddDate(0, 0, 1)
+	case Month:
+		return t.AddDate(0, 1, 0)
+	case Year:
+		return t.AddDate(1, 0, 0)
+	}
+	return t
+}
+
 type DateTimeFormat struct {
 	Layout string
 	// Used to indicate that the layout doesn't have a year in it, so assume
@@ -16,23 +54,26 @@ type DateTimeFormat struct {
 	UseCurrentYear   bool
 	UseCurrentDay    bool
 	UseLocalTimezone bool
+	Granularity      Granularity
 }
 
 // These are the formats that are valid literals when embedded unquoted in a jt
 // script.
 var LiteralFormats = []DateTimeFormat{
-	{"2006-01-02T15:04:05.000Z", false, false, false},
-	{"2006-01-02T15:04:05", false, false, true},
-	{"2006-01-02T15:04", false, false, true},
-	{"2006-01-02T15", false, false, true},
-	{"2006-01-02T", false, false, true},
-	{"01-02T", true, false, true},
-	{"20060102T15:04:05.000Z", false, false, false},
-	{"20060102T15:04:05", false, false, true},
-	{"20060102T15:04", false, false, true},
-	{"20060102T15", false, false, true},
-	{"20060102T", false, false, true},
-	{"0102T", true, false, true},
+	{"2006-01-02T15:04:05.000Z", false, false, false, Millisecond},
+	{"2006-01-02T15:04:05", false, false, true, Second},
+	{"2006-01-02T15:04", false, false, true, Minute},
+	{"2006-01-02T15", false, false, true, Hour},
+	{"2006-01-02T", false, false, true, Day},
+	{"2006-01T", false, false, true, Month},
+	{"2006T", false, false, true, Year},
+	{"01-02T", true, false, true, Day},
+	{"20060102T15:04:05.000Z", false, false, false, Millisecond},
+	{"20060102T15:04:05", false, false, true, Second},
+	{"20060102T15:04", false, false, true, Minute},
+	{"20060102T15", false, false, true, Hour},
+	{"20060102T", false, false, true, Day},
+	{"200601T", false, false, true, Month},
 }
 
 // These are the formats that will be attempted when an unknown value is
@@ -40,31 +81,103 @@ var LiteralFormats = []DateTimeFormat{
 // that by coomparing a value to a date type indicates the user is hoping for a
 // valid date to be present.
 var CoercionFormats = []DateTimeFormat{
-	{"2006-01-02T15:04:05.000Z", false, false, false},
-	{"2006-01-02T15:04:05", false, false, true},
-	{"2006-01-02T15:04", false, false, true},
-	{"2006-01-02T15", false, false, true},
-	{"2006-01-02T", false, false, true},
-	{"2006-01-02", false, false, true},
-	{"20060102T15:04:05.000Z", false, false, false},
-	{"20060102T15:04:05", false, false, true},
-	{"20060102T15:04", false, false, true},
-	{"20060102T15", false, false, true},
-	{"20060102T", false, false, true},
-	{"20060102", false, false, true},
-	{"Mon Jan 2 15:04:05 PST 2006", false, false, false}, // Output of `date`
-	{"Monday Jan 2 15:04:05 PST 2006", false, false, false},
-	{"Mon Jan 2 15:04:05", true, false, true},
-	{"Monday Jan 2 15:04:05", true, false, true},
-	{"2Jan06", false, false, true}, // date/time in `ps -ef`
-	{"15:05AM", true, true, true},  // date/time in `ps -ef`
-	{"Jan 2 15:04", true, false, true},
-	{"Jan 2, 2006", false, false, true},
-	{"January 2, 2006", false, false, true},
-	{"Jan _2 2006", false, false, true}, // Older files in `ls -l`
+	{"2006-01-02T15:04:05.000Z", false, false, false, Millisecond},
+	{"2006-01-02T15:04:05", false, false, true, Second},
+	{"2006-01-02T15:04", false, false, true, Minute},
+	{"2006-01-02T15", false, false, true, Hour},
+	{"2006-01-02T", false, false, true, Day},
+	{"2006-01-02", false, false, true, Day},
+	{"20060102T15:04:05.000Z", false, false, false, Millisecond},
+	{"20060102T15:04:05", false, false, true, Second},
+	{"20060102T15:04", false, false, true, Minute},
+	{"20060102T15", false, false, true, Hour},
+	{"20060102T", false, false, true, Day},
+	{"20060102", false, false, true, Day},
+	{"Mon Jan 2 15:04:05 PST 2006", false, false, false, Second}, // Output of `date`
+	{"Monday Jan 2 15:04:05 PST 2006", false, false, false, Second},
+	{"Mon Jan 2 15:04:05", true, false, true, Second},
+	{"Monday Jan 2 15:04:05", true, false, true, Second},
+	{"2Jan06", false, false, true, Day},   // date/time in `ps -ef`
+	{"15:05AM", true, true, true, Minute}, // date/time in `ps -ef`
+	{"Jan 2 15:04", true, false, true, Minute},
+	{"Jan 2, 2006", false, false, true, Day},
+	{"January 2, 2006", false, false, true, Day},
+	{"Jan _2 2006", false, false, true, Day}, // Older files in `ls -l`
+}
+
+// literalLayouts are the layouts that write a date/time as a literal, to the
+// precision of each granularity.
+var literalLayouts = map[Granularity]string{
+	Millisecond: "2006-01-02T15:04:05.000Z",
+	Second:      "2006-01-02T15:04:05",
+	Minute:      "2006-01-02T15:04",
+	Hour:        "2006-01-02T15",
+	Day:         "2006-01-02T",
+	Month:       "2006-01T",
+	Year:        "2006T",
+}
+
+// Period is a span of time that starts at Start and is as long as its
+// Granularity. A period with the Instant granularity has no length at all.
+type Period struct {
+	Start       time.Time
+	Granularity Granularity
+}
+
+func (p Period) End() time.Time {
+	return p.Granularity.End(p.Start)
+}
+
+// String writes the period as a date/time literal, to the precision of its
+// granularity, so the whole of June 2013 is 2013-06T. An instant is written to
+// the second, or to the millisecond when it has a fraction of a second.
+func (p Period) String() string {
+	g := p.Granularity
+	if g == Instant {
+		g = Second
+		if p.Start.Nanosecond() != 0 {
+			g = Millisecond
+		}
+	}
+	if g == Millisecond {
+		// The Z of a millisecond literal is UTC.
+		return p.Start.UTC().Format(literalLayouts[g])
+	}
+	return p.Start.Format(literalLayouts[g])
+}
+
+// Equal reports whether the finer of the two periods falls within the coarser
+// one, so 2013-06-01T10:15 is equal to 2013T, but 2013-06-01T10:15 is not
+// equal to 2013-06-01T10:16.
+func (p Period) Equal(o Period) bool {
+	fine, coarse := p, o
+	if fine.Granularity > coarse.Granularity {
+		fine, coarse = coarse, fine
+	}
+	if coarse.Granularity == Instant {
+		return fine.Start.Equal(coarse.Start)
+	}
+	return !fine.Start.Before(coarse.Start) && fine.Start.Before(coarse.End())
+}
+
+// Before reports whether the period is over before the other period starts.
+func (p Period) Before(o Period) bool {
+	return !p.End().After(o.Start) && !p.Equal(o)
+}
+
+// After reports whether the period starts after the other period is over.
+func (p Period) After(o Period) bool {
+	return o.Before(p)
 }
 
 func ParseDateTime(formats []DateTimeFormat, str string) (time.Time, error) {
+	p, err := ParsePeriod(formats, str)
+	return p.Start, err
+}
+
+// ParsePeriod parses a date/time, keeping the granularity of the format it
+// matched.
+func ParsePeriod(formats []DateTimeFormat, str string) (Period, error) {
 	for _, f := range formats {
 		if t, err := time.Parse(f.Layout, str); err == nil {
 			if f.UseCurrentYear {
@@ -97,8 +210,8 @@ func ParseDateTime(formats []DateTimeFormat, str string) (time.Time, error) {
 					t.Nanosecond(),
 					time.Now().Location())
 			}
-			return t, nil
+			return Period{Start: t, Granularity: f.Granularity}, nil
 		}
 	}
-	return Zero, fmt.Errorf("Unable to convert %q to a date", str)
+	return Period{Start: Zero}, fmt.Errorf("Unable to convert %q to a date", str)
 }
@@ -0,0 +1,96 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    Period
+	}{
+		{"2013T", Period{time.Date(2013, 1, 1, 0, 0, 0, 0, time.Local), Year}},
+		{"2013-06T", Period{time.Date(2013, 6, 1, 0, 0, 0, 0, time.Local), Month}},
+		{"201306T", Period{time.Date(2013, 6, 1, 0, 0, 0, 0, time.Local), Month}},
+		{"2013-06-01T", Period{time.Date(2013, 6, 1, 0, 0, 0, 0, time.Local), Day}},
+		{"2013-06-01T10", Period{time.Date(2013, 6, 1, 10, 0, 0, 0, time.Local), Hour}},
+		{"2013-06-01T10:15", Period{time.Date(2013, 6, 1, 10, 15, 0, 0, time.Local), Minute}},
+		{"2013-06-01T10:15:30", Period{time.Date(2013, 6, 1, 10, 15, 30, 0, time.Local), Second}},
+		{"2013-06-01T10:15:30.500Z", Period{time.Date(2013, 6, 1, 10, 15, 30, 500000000, time.UTC), Millisecond}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.literal, func(t *testing.T) {
+			got, err := ParsePeriod(LiteralFormats, test.literal)
+
+			assert.NoError(t, err)
+			assert.True(t, test.want.Start.Equal(got.Start), "got %s", got.Start)
+			assert.Equal(t, test.want.Granularity, got.Granularity)
+		})
+	}
+}
+
+func TestPeriodString(t *testing.T) {
+	tests := []struct {
+		period Period
+		want   string
+	}{
+		{Period{time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC), Year}, "2013T"},
+		{Period{time.Date(2013, 6, 1, 0, 0, 0, 0, time.UTC), Month}, "2013-06T"},
+		{Period{time.Date(2013, 6, 1, 0, 0, 0, 0, time.UTC), Day}, "2013-06-01T"},
+		{Period{time.Date(2013, 6, 1, 10, 0, 0, 0, time.UTC), Hour}, "2013-06-01T10"},
+		{Period{time.Date(2013, 6, 1, 10, 15, 0, 0, time.UTC), Minute}, "2013-06-01T10:15"},
+		{Period{time.Date(2013, 6, 1, 10, 15, 30, 0, time.UTC), Second}, "2013-06-01T10:15:30"},
+		{Period{time.Date(2013, 6, 1, 10, 15, 30, 500000000, time.UTC), Millisecond}, "2013-06-01T10:15:30.500Z"},
+		{Period{Start: time.Date(2013, 6, 1, 10, 15, 30, 0, time.UTC)}, "2013-06-01T10:15:30"},
+		{Period{Start: time.Date(2013, 6, 1, 10, 15, 30, 250000000, time.UTC)}, "2013-06-01T10:15:30.250Z"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			assert.Equal(t, test.want, test.period.String())
+
+			// Anything but an instant can be read back as the same literal.
+			if test.period.Granularity != Instant {
+				got, err := ParsePeriod(LiteralFormats, test.want)
+				assert.NoError(t, err)
+				assert.Equal(t, test.period.Granularity, got.Granularity)
+			}
+		})
+	}
+}
+
+func TestPeriodComparisons(t *testing.T) {
+	year := Period{time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC), Year}
+	june := Period{time.Date(2013, 6, 1, 0, 0, 0, 0, time.UTC), Month}
+	tests := []struct {
+		name   string
+		left   Period
+		right  Period
+		before bool
+		equal  bool
+		after  bool
+	}{
+		{"instant before", Period{Start: time.Date(2012, 12, 31, 23, 59, 59, 0, time.UTC)}, year, true, false, false},
+		{"instant at start", Period{Start: year.Start}, year, false, true, false},
+		{"instant inside", Period{Start: time.Date(2013, 7, 4, 12, 0, 0, 0, time.UTC)}, year, false, true, false},
+		{"instant at end", Period{Start: year.End()}, year, false, false, true},
+		{"period inside", june, year, false, true, false},
+		{"period outside", june, Period{time.Date(2013, 7, 1, 0, 0, 0, 0, time.UTC), Day}, true, false, false},
+		{"same instant", Period{Start: year.Start}, Period{Start: year.Start}, false, true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.before, test.left.Before(test.right), "before")
+			assert.Equal(test.equal, test.left.Equal(test.right), "equal")
+			assert.Equal(test.equal, test.right.Equal(test.left), "equal reversed")
+			assert.Equal(test.after, test.left.After(test.right), "after")
+		})
+	}
+}
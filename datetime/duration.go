@@ -0,0 +1,158 @@
+package datetime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a length of time. Years, months and days don't always have the
+// same length, a month can be 28 to 31 days and a day can be 23 to 25 hours
+// when daylight savings time changes, so they are kept separate from the
+// clock time, and only resolved against a particular date when the duration
+// is added to that date.
+type Duration struct {
+	Years  int
+	Months int
+	Days   int
+	Clock  time.Duration
+}
+
+// The units allowed in a duration literal. Upper case units are calendar
+// units, lower case units are clock units, so `5M` is five months and `5m` is
+// five minutes. `H` and `S` aren't ambiguous, so they are allowed too.
+var durationUnits = "YMWDhHmsS"
+
+// ParseDuration parses a duration literal, like `5m`, `2h`, `3D` or `1Y2M`. A
+// literal is a sequence of integers, each followed by a unit.
+func ParseDuration(s string) (Duration, error) {
+	d := Duration{}
+	rest := s
+	if len(rest) == 0 {
+		return d, fmt.Errorf("Unable to convert %q to a duration", s)
+	}
+	for len(rest) > 0 {
+		i := strings.IndexAny(rest, durationUnits)
+		if i <= 0 {
+			return Duration{}, fmt.Errorf("Unable to convert %q to a duration", s)
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil || strings.ContainsAny(rest[:i], "+-") {
+			return Duration{}, fmt.Errorf("Unable to convert %q to a duration", s)
+		}
+		switch rest[i] {
+		case 'Y':
+			d.Years += n
+		case 'M':
+			d.Months += n
+		case 'W':
+			d.Days += 7 * n
+		case 'D':
+			d.Days += n
+		case 'h', 'H':
+			d.Clock += time.Duration(n) * time.Hour
+		case 'm':
+			d.Clock += time.Duration(n) * time.Minute
+		case 's', 'S':
+			d.Clock += time.Duration(n) * time.Second
+		}
+		rest = rest[i+1:]
+	}
+	return d, nil
+}
+
+// Between returns the duration from the start time to the end time. The
+// result is negative if end is before start.
+func Between(start, end time.Time) Duration {
+	return Duration{Clock: end.Sub(start)}
+}
+
+// AddTo returns the time the duration is after t.
+func (d Duration) AddTo(t time.Time) time.Time {
+	return t.AddDate(d.Years, d.Months, d.Days).Add(d.Clock)
+}
+
+func (d Duration) Add(o Duration) Duration {
+	return Duration{
+		Years:  d.Years + o.Years,
+		Months: d.Months + o.Months,
+		Days:   d.Days + o.Days,
+		Clock:  d.Clock + o.Clock,
+	}
+}
+
+func (d Duration) Negate() Duration {
+	return Duration{
+		Years:  -d.Years,
+		Months: -d.Months,
+		Days:   -d.Days,
+		Clock:  -d.Clock,
+	}
+}
+
+// Granularity is the precision of the smallest unit in the duration, so 1D is
+// a Day, and 1D2h is an Hour.
+func (d Duration) Granularity() Granularity {
+	switch {
+	case d.Clock%time.Millisecond != 0:
+		return Instant
+	case d.Clock%time.Second != 0:
+		return Millisecond
+	case d.Clock%time.Minute != 0:
+		return Second
+	case d.Clock%time.Hour != 0:
+		return Minute
+	case d.Clock != 0:
+		return Hour
+	case d.Days != 0:
+		return Day
+	case d.Months != 0:
+		return Month
+	}
+	return Year
+}
+
+// Approximate returns the length of the duration, using the average length of
+// a year, a month and a day. It is only intended for comparing durations.
+func (d Duration) Approximate() time.Duration {
+	const day = 24 * time.Hour
+	const year = 365*day + day/4 - day/100 + day/400
+	return time.Duration(d.Years)*year +
+		time.Duration(d.Months)*(year/12) +
+		time.Duration(d.Days)*day +
+		d.Clock
+}
+
+// String formats the duration the same way it would be written as a literal.
+func (d Duration) String() string {
+	if d.Years <= 0 && d.Months <= 0 && d.Days <= 0 && d.Clock <= 0 && d != (Duration{}) {
+		return "-" + d.Negate().String()
+	}
+
+	sb := strings.Builder{}
+	for _, p := range []struct {
+		n    int
+		unit string
+	}{{d.Years, "Y"}, {d.Months, "M"}, {d.Days, "D"}} {
+		if p.n != 0 {
+			fmt.Fprintf(&sb, "%d%s", p.n, p.unit)
+		}
+	}
+
+	clock := d.Clock
+	if clock < 0 {
+		sb.WriteString("-")
+		clock = -clock
+	}
+	if h := clock / time.Hour; h != 0 {
+		fmt.Fprintf(&sb, "%dh", h)
+	}
+	if m := clock % time.Hour / time.Minute; m != 0 {
+		fmt.Fprintf(&sb, "%dm", m)
+	}
+	if s := clock % time.Minute; s != 0 || sb.Len() == 0 {
+		fmt.Fprintf(&sb, "%ss", strconv.FormatFloat(s.Seconds(), 'f', -1, 64))
+	}
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    Duration
+	}{
+		{"5M", Duration{Months: 5}},
+		{"5m", Duration{Clock: 5 * time.Minute}},
+		{"2h", Duration{Clock: 2 * time.Hour}},
+		{"2H", Duration{Clock: 2 * time.Hour}},
+		{"3D", Duration{Days: 3}},
+		{"2W", Duration{Days: 14}},
+		{"1Y2M", Duration{Years: 1, Months: 2}},
+		{"1D12h30s", Duration{Days: 1, Clock: 12*time.Hour + 30*time.Second}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.literal, func(t *testing.T) {
+			got, err := ParseDuration(test.literal)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestParseDurationErrors(t *testing.T) {
+	for _, literal := range []string{"", "5", "M", "5X", "-5m", "5m3"} {
+		t.Run(literal, func(t *testing.T) {
+			_, err := ParseDuration(literal)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDurationAddTo(t *testing.T) {
+	start := time.Date(2020, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC), Duration{Months: 1}.AddTo(start))
+	assert.Equal(t, time.Date(2021, 2, 3, 9, 55, 0, 0, time.UTC), Duration{Years: 1, Days: 3, Clock: -5 * time.Minute}.AddTo(start))
+}
+
+func TestDurationString(t *testing.T) {
+	tests := []struct {
+		duration Duration
+		want     string
+	}{
+		{Duration{}, "0s"},
+		{Duration{Years: 1, Months: 2}, "1Y2M"},
+		{Duration{Clock: 90 * time.Minute}, "1h30m"},
+		{Duration{Clock: 1500 * time.Millisecond}, "1.5s"},
+		{Duration{Days: -3, Clock: -time.Hour}, "-3D1h"},
+		{Duration{Days: 1, Clock: -time.Hour}, "1D-1h"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			assert.Equal(t, test.want, test.duration.String())
+		})
+	}
+}
+
+func TestDurationGranularity(t *testing.T) {
+	tests := []struct {
+		duration Duration
+		want     Granularity
+	}{
+		{Duration{}, Year},
+		{Duration{Years: 2}, Year},
+		{Duration{Years: 1, Months: 2}, Month},
+		{Duration{Days: 7}, Day},
+		{Duration{Days: 1, Clock: 2 * time.Hour}, Hour},
+		{Duration{Clock: 90 * time.Minute}, Minute},
+		{Duration{Clock: -30 * time.Second}, Second},
+		{Duration{Clock: 1500 * time.Millisecond}, Millisecond},
+		{Duration{Clock: time.Nanosecond}, Instant},
+	}
+
+	for _, test := range tests {
+		t.Run(test.duration.String(), func(t *testing.T) {
+			assert.Equal(t, test.want, test.duration.Granularity())
+		})
+	}
+}
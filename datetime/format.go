@@ -0,0 +1,175 @@
+package datetime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Format renders a date/time with a strftime style pattern. Each `%` followed
+// by a letter is replaced with part of the date/time, everything else is
+// copied as is.
+//
+//	%a  abbreviated weekday name (Mon)
+//	%A  full weekday name (Monday)
+//	%b  abbreviated month name (Jan), also %h
+//	%B  full month name (January)
+//	%c  date and time (Mon Jan  2 15:04:05 2006)
+//	%C  century (20)
+//	%d  day of the month (02)
+//	%D  same as %m/%d/%y
+//	%e  day of the month, space padded ( 2)
+//	%F  same as %Y-%m-%d
+//	%G  ISO 8601 week based year (2006)
+//	%g  ISO 8601 week based year, without the century (06)
+//	%H  hour, 24 hour clock (15)
+//	%I  hour, 12 hour clock (03)
+//	%j  day of the year (002)
+//	%k  hour, 24 hour clock, space padded (15)
+//	%l  hour, 12 hour clock, space padded ( 3)
+//	%L  milliseconds (000)
+//	%m  month (01)
+//	%M  minute (04)
+//	%n  a newline
+//	%N  nanoseconds (000000000)
+//	%p  AM or PM
+//	%P  am or pm
+//	%r  same as %I:%M:%S %p
+//	%R  same as %H:%M
+//	%s  seconds since 1970-01-01T00:00:00Z
+//	%S  second (05)
+//	%t  a tab
+//	%T  same as %H:%M:%S
+//	%u  day of the week, 1 is Monday and 7 is Sunday
+//	%U  week of the year, starting on the first Sunday (00-53)
+//	%V  ISO 8601 week of the year (01-53)
+//	%w  day of the week, 0 is Sunday and 6 is Saturday
+//	%W  week of the year, starting on the first Monday (00-53)
+//	%x  same as %m/%d/%y
+//	%X  same as %H:%M:%S
+//	%y  year, without the century (06)
+//	%Y  year (2006)
+//	%z  timezone offset (-0700)
+//	%:z timezone offset with a colon (-07:00)
+//	%Z  timezone abbreviation (MST)
+//	%%  a literal %
+func Format(t time.Time, pattern string) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i+1 == len(pattern) {
+			sb.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		if pattern[i] == ':' && i+1 < len(pattern) && pattern[i+1] == 'z' {
+			i++
+			sb.WriteString(t.Format("-07:00"))
+			continue
+		}
+		if s, ok := formatDirective(t, pattern[i]); ok {
+			sb.WriteString(s)
+		} else {
+			// An unknown directive is left as it was written.
+			sb.WriteByte('%')
+			sb.WriteByte(pattern[i])
+		}
+	}
+	return sb.String()
+}
+
+func formatDirective(t time.Time, directive byte) (string, bool) {
+	switch directive {
+	case 'a':
+		return t.Format("Mon"), true
+	case 'A':
+		return t.Format("Monday"), true
+	case 'b', 'h':
+		return t.Format("Jan"), true
+	case 'B':
+		return t.Format("January"), true
+	case 'c':
+		return t.Format("Mon Jan _2 15:04:05 2006"), true
+	case 'C':
+		return fmt.Sprintf("%02d", t.Year()/100), true
+	case 'd':
+		return t.Format("02"), true
+	case 'D', 'x':
+		return t.Format("01/02/06"), true
+	case 'e':
+		return t.Format("_2"), true
+	case 'F':
+		return t.Format("2006-01-02"), true
+	case 'G':
+		year, _ := t.ISOWeek()
+		return fmt.Sprintf("%04d", year), true
+	case 'g':
+		year, _ := t.ISOWeek()
+		return fmt.Sprintf("%02d", year%100), true
+	case 'H':
+		return t.Format("15"), true
+	case 'I':
+		return t.Format("03"), true
+	case 'j':
+		return fmt.Sprintf("%03d", t.YearDay()), true
+	case 'k':
+		return fmt.Sprintf("%2d", t.Hour()), true
+	case 'l':
+		hour := t.Hour() % 12
+		if hour == 0 {
+			hour = 12
+		}
+		return fmt.Sprintf("%2d", hour), true
+	case 'L':
+		return fmt.Sprintf("%03d", t.Nanosecond()/int(time.Millisecond)), true
+	case 'm':
+		return t.Format("01"), true
+	case 'M':
+		return t.Format("04"), true
+	case 'n':
+		return "\n", true
+	case 'N':
+		return fmt.Sprintf("%09d", t.Nanosecond()), true
+	case 'p':
+		return t.Format("PM"), true
+	case 'P':
+		return t.Format("pm"), true
+	case 'r':
+		return t.Format("03:04:05 PM"), true
+	case 'R':
+		return t.Format("15:04"), true
+	case 's':
+		return fmt.Sprintf("%d", t.Unix()), true
+	case 'S':
+		return t.Format("05"), true
+	case 't':
+		return "\t", true
+	case 'T', 'X':
+		return t.Format("15:04:05"), true
+	case 'u':
+		weekday := int(t.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		return fmt.Sprintf("%d", weekday), true
+	case 'U':
+		return fmt.Sprintf("%02d", (t.YearDay()+6-int(t.Weekday()))/7), true
+	case 'V':
+		_, week := t.ISOWeek()
+		return fmt.Sprintf("%02d", week), true
+	case 'w':
+		return fmt.Sprintf("%d", t.Weekday()), true
+	case 'W':
+		return fmt.Sprintf("%02d", (t.YearDay()+6-(int(t.Weekday())+6)%7)/7), true
+	case 'y':
+		return t.Format("06"), true
+	case 'Y':
+		return fmt.Sprintf("%04d", t.Year()), true
+	case 'z':
+		return t.Format("-0700"), true
+	case 'Z':
+		return t.Format("MST"), true
+	case '%':
+		return "%", true
+	}
+	return "", false
+}
@@ -0,0 +1,42 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	// 2006-01-02 is a Monday, 2010-01-03 is a Sunday in the last ISO week of
+	// 2009.
+	monday := time.Date(2006, 1, 2, 15, 4, 5, 123456789, time.FixedZone("MST", -7*60*60))
+	sunday := time.Date(2010, 1, 3, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t       time.Time
+		pattern string
+		want    string
+	}{
+		{monday, "%Y-%m-%dT%H:%M:%S", "2006-01-02T15:04:05"},
+		{monday, "%F %T", "2006-01-02 15:04:05"},
+		{monday, "%a %A %b %h %B", "Mon Monday Jan Jan January"},
+		{monday, "%c", "Mon Jan  2 15:04:05 2006"},
+		{monday, "%C %y %D %e", "20 06 01/02/06  2"},
+		{monday, "%I %l %p %P %r %R", "03  3 PM pm 03:04:05 PM 15:04"},
+		{monday, "%j %u %w %U %W", "002 1 1 01 01"},
+		{monday, "%G %g %V", "2006 06 01"},
+		{monday, "%L %N", "123 123456789"},
+		{monday, "%s", "1136239445"},
+		{monday, "%z %:z %Z", "-0700 -07:00 MST"},
+		{monday, "100%% %t%n", "100% \t\n"},
+		{monday, "%q %", "%q %"},
+		{sunday, "%G-W%V-%u", "2009-W53-7"},
+		{sunday, "%U %W %j", "01 00 003"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			assert.Equal(t, test.want, Format(test.t, test.pattern))
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package datetime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Parse parses a date/time written with a strftime style pattern, the same
+// patterns that Format uses. The period is as precise as the smallest unit in
+// the pattern, so "%Y-%m" is a whole month. Whitespace in the pattern matches
+// any amount of whitespace. A date/time without a timezone is in the local
+// timezone, one without a year is in the current year, and one with only a
+// time is today.
+func Parse(pattern, s string) (Period, error) {
+	p := &dateParser{input: s, month: 1, day: 1, location: time.Local}
+	if err := p.parse(pattern); err != nil {
+		return Period{Start: Zero}, fmt.Errorf("Unable to convert %q to a date with %q: %v", s, pattern, err)
+	}
+	if p.input != "" {
+		return Period{Start: Zero}, fmt.Errorf("Unable to convert %q to a date with %q: %q is left over", s, pattern, p.input)
+	}
+	return p.period()
+}
+
+// dateParser consumes the input as it is matched by the pattern, collecting
+// the parts of the date/time.
+type dateParser struct {
+	input string
+
+	year, month, day, yearDay            int
+	hour, minute, second, nanosecond     int
+	pm, hasYear, hasDate, hasPM, hasUnix bool
+	unix                                 int64
+	location                             *time.Location
+	granularity                          Granularity
+	hasUnits                             bool
+}
+
+func (p *dateParser) parse(pattern string) error {
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '%' && i+1 < len(pattern):
+			i++
+			directive := string(pattern[i])
+			if pattern[i] == ':' && i+1 < len(pattern) && pattern[i+1] == 'z' {
+				i++
+				directive = ":z"
+			}
+			if err := p.directive(directive); err != nil {
+				return err
+			}
+		case unicode.IsSpace(rune(c)):
+			p.input = strings.TrimLeftFunc(p.input, unicode.IsSpace)
+		default:
+			if !strings.HasPrefix(p.input, string(c)) {
+				return fmt.Errorf("expected %q at %q", c, p.input)
+			}
+			p.input = p.input[1:]
+		}
+	}
+	return nil
+}
+
+// expansions are the directives that are shorthand for a longer pattern.
+var expansions = map[string]string{
+	"c": "%a %b %e %H:%M:%S %Y",
+	"D": "%m/%d/%y",
+	"x": "%m/%d/%y",
+	"F": "%Y-%m-%d",
+	"r": "%I:%M:%S %p",
+	"R": "%H:%M",
+	"T": "%H:%M:%S",
+	"X": "%H:%M:%S",
+}
+
+func (p *dateParser) directive(directive string) error {
+	if expansion, ok := expansions[directive]; ok {
+		return p.parse(expansion)
+	}
+
+	var err error
+	switch directive {
+	case "Y":
+		p.year, err = p.number(4, 4, 0, 9999)
+		p.hasYear = true
+		p.finer(Year)
+	case "y":
+		p.year, err = p.number(2, 2, 0, 99)
+		// The same as POSIX, 69 to 99 are the 1900s, 00 to 68 the 2000s.
+		if p.year < 69 {
+			p.year += 2000
+		} else {
+			p.year += 1900
+		}
+		p.hasYear = true
+		p.finer(Year)
+	case "m":
+		p.month, err = p.number(1, 2, 1, 12)
+		p.hasDate = true
+		p.finer(Month)
+	case "b", "h", "B":
+		p.month, err = p.name(monthNames)
+		p.hasDate = true
+		p.finer(Month)
+	case "d", "e":
+		p.space()
+		p.day, err = p.number(1, 2, 1, 31)
+		p.hasDate = true
+		p.finer(Day)
+	case "j":
+		p.yearDay, err = p.number(1, 3, 1, 366)
+		p.hasDate = true
+		p.finer(Day)
+	case "a", "A":
+		_, err = p.name(dayNames)
+	case "H", "k":
+		p.space()
+		p.hour, err = p.number(1, 2, 0, 23)
+		p.finer(Hour)
+	case "I", "l":
+		p.space()
+		p.hour, err = p.number(1, 2, 1, 12)
+		p.hasPM = true
+		p.finer(Hour)
+	case "p", "P":
+		switch {
+		case strings.HasPrefix(strings.ToUpper(p.input), "AM"):
+		case strings.HasPrefix(strings.ToUpper(p.input), "PM"):
+			p.pm = true
+		default:
+			return fmt.Errorf("expected AM or PM at %q", p.input)
+		}
+		p.input = p.input[2:]
+	case "M":
+		p.minute, err = p.number(1, 2, 0, 59)
+		p.finer(Minute)
+	case "S":
+		p.second, err = p.number(1, 2, 0, 60)
+		p.finer(Second)
+	case "L":
+		var ms int
+		ms, err = p.number(3, 3, 0, 999)
+		p.nanosecond = ms * int(time.Millisecond)
+		p.finer(Millisecond)
+	case "N":
+		digits := len(p.input) - len(strings.TrimLeftFunc(p.input, unicode.IsDigit))
+		p.nanosecond, err = p.number(1, 9, 0, 999999999)
+		for ; digits < 9; digits++ {
+			p.nanosecond *= 10
+		}
+		p.finer(Instant)
+	case "s":
+		var seconds int
+		seconds, err = p.number(1, 18, 0, 999999999999999999)
+		p.unix = int64(seconds)
+		p.hasUnix = true
+		p.finer(Second)
+	case "z", ":z":
+		err = p.offset()
+	case "Z":
+		zone := p.input[:len(p.input)-len(strings.TrimLeftFunc(p.input, unicode.IsUpper))]
+		if zone == "" {
+			return fmt.Errorf("expected a timezone at %q", p.input)
+		}
+		if zone == "UTC" || zone == "GMT" || zone == "Z" {
+			p.location = time.UTC
+		}
+		p.input = p.input[len(zone):]
+	case "n", "t":
+		p.space()
+	case "%":
+		if !strings.HasPrefix(p.input, "%") {
+			return fmt.Errorf("expected %% at %q", p.input)
+		}
+		p.input = p.input[1:]
+	default:
+		return fmt.Errorf("%%%s can't be used to read a date", directive)
+	}
+	return err
+}
+
+// finer makes the granularity of the period finer, if g is finer than the
+// units parsed so far.
+func (p *dateParser) finer(g Granularity) {
+	if !p.hasUnits || g < p.granularity {
+		p.granularity = g
+		p.hasUnits = true
+	}
+}
+
+func (p *dateParser) space() {
+	p.input = strings.TrimLeft(p.input, " ")
+}
+
+// number reads an integer with between min and max digits, that has to be
+// between low and high.
+func (p *dateParser) number(min, max, low, high int) (int, error) {
+	n, i := 0, 0
+	for ; i < max && i < len(p.input) && p.input[i] >= '0' && p.input[i] <= '9'; i++ {
+		n = n*10 + int(p.input[i]-'0')
+	}
+	if i < min {
+		return 0, fmt.Errorf("expected a number at %q", p.input)
+	}
+	if n < low || n > high {
+		return 0, fmt.Errorf("%s is out of range", p.input[:i])
+	}
+	p.input = p.input[i:]
+	return n, nil
+}
+
+var monthNames = []string{"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December"}
+var dayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+// name reads a name, or its three letter abbreviation, ignoring case, and
+// returns its position in names, starting at 1.
+func (p *dateParser) name(names []string) (int, error) {
+	lower := strings.ToLower(p.input)
+	for i, name := range names {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(lower, name) {
+			p.input = p.input[len(name):]
+			return i + 1, nil
+		} else if strings.HasPrefix(lower, name[:3]) {
+			p.input = p.input[3:]
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("expected a name at %q", p.input)
+}
+
+// offset reads a timezone offset, -0700, -07:00 or Z.
+func (p *dateParser) offset() error {
+	if strings.HasPrefix(p.input, "Z") {
+		p.input = p.input[1:]
+		p.location = time.UTC
+		return nil
+	}
+	if p.input == "" || (p.input[0] != '+' && p.input[0] != '-') {
+		return fmt.Errorf("expected a timezone offset at %q", p.input)
+	}
+	sign := 1
+	if p.input[0] == '-' {
+		sign = -1
+	}
+	p.input = p.input[1:]
+	hours, err := p.number(2, 2, 0, 23)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(p.input, ":") {
+		p.input = p.input[1:]
+	}
+	minutes, err := p.number(2, 2, 0, 59)
+	if err != nil {
+		return err
+	}
+	p.location = time.FixedZone("", sign*(hours*60*60+minutes*60))
+	return nil
+}
+
+func (p *dateParser) period() (Period, error) {
+	if p.hasUnix {
+		return Period{Start: time.Unix(p.unix, 0).In(p.location), Granularity: p.granularity}, nil
+	}
+	hour := p.hour
+	if p.hasPM {
+		hour %= 12
+		if p.pm {
+			hour += 12
+		}
+	}
+	now := time.Now()
+	year, month, day := p.year, time.Month(p.month), p.day
+	if !p.hasYear {
+		year = now.Year()
+		if !p.hasDate {
+			month, day = now.Month(), now.Day()
+		}
+	}
+	var t time.Time
+	if p.yearDay > 0 {
+		t = time.Date(year, 1, p.yearDay, hour, p.minute, p.second, p.nanosecond, p.location)
+		if t.Year() != year {
+			return Period{Start: Zero}, fmt.Errorf("Unable to convert to a date: day %d isn't in %d", p.yearDay, year)
+		}
+	} else {
+		t = time.Date(year, month, day, hour, p.minute, p.second, p.nanosecond, p.location)
+		if t.Day() != day {
+			return Period{Start: Zero}, fmt.Errorf("Unable to convert to a date: %s has no day %d", month, day)
+		}
+	}
+	return Period{Start: t, Granularity: p.granularity}, nil
+}
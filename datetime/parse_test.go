@@ -0,0 +1,73 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	local := func(year int, month time.Month, day, hour, min, sec, nsec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, nsec, time.Local)
+	}
+	now := time.Now()
+	tests := []struct {
+		pattern string
+		s       string
+		want    Period
+	}{
+		{"%d/%m/%Y", "25/12/2019", Period{local(2019, 12, 25, 0, 0, 0, 0), Day}},
+		{"%Y-%m", "2019-06", Period{local(2019, 6, 1, 0, 0, 0, 0), Month}},
+		{"%Y", "2019", Period{local(2019, 1, 1, 0, 0, 0, 0), Year}},
+		{"%F %T", "2019-06-01 10:15:30", Period{local(2019, 6, 1, 10, 15, 30, 0), Second}},
+		{"%FT%T.%L%z", "2019-06-01T10:15:30.250+0200", Period{time.Date(2019, 6, 1, 10, 15, 30, 250000000, time.FixedZone("", 2*60*60)), Millisecond}},
+		{"%FT%T%:z", "2019-06-01T10:15:30Z", Period{time.Date(2019, 6, 1, 10, 15, 30, 0, time.UTC), Second}},
+		{"%b %e %Y", "Jan  2 2006", Period{local(2006, 1, 2, 0, 0, 0, 0), Day}},
+		{"%A, %B %d, %Y", "monday, january 02, 2006", Period{local(2006, 1, 2, 0, 0, 0, 0), Day}},
+		{"%D %r", "01/02/06 03:04:05 PM", Period{local(2006, 1, 2, 15, 4, 5, 0), Second}},
+		{"%m/%d/%y %I%p", "12/31/99 12AM", Period{local(1999, 12, 31, 0, 0, 0, 0), Hour}},
+		{"%Y %j", "2020 366", Period{local(2020, 12, 31, 0, 0, 0, 0), Day}},
+		{"%Y%m%d%H%M", "201906011015", Period{local(2019, 6, 1, 10, 15, 0, 0), Minute}},
+		{"%S.%N", "05.5", Period{local(now.Year(), now.Month(), now.Day(), 0, 0, 5, 500000000), Instant}},
+		{"%s", "1136239445", Period{time.Unix(1136239445, 0), Second}},
+		{"100%% %Y", "100%  2019", Period{local(2019, 1, 1, 0, 0, 0, 0), Year}},
+		{"%d %b", "02 Mar", Period{local(now.Year(), 3, 2, 0, 0, 0, 0), Day}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			assert := assert.New(t)
+
+			got, err := Parse(test.pattern, test.s)
+
+			assert.NoError(err)
+			assert.True(test.want.Start.Equal(got.Start), "want %s, got %s", test.want.Start, got.Start)
+			assert.Equal(test.want.Granularity, got.Granularity)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+	}{
+		{"%d/%m/%Y", "2019-12-25"},
+		{"%Y-%m-%d", "2019-13-01"},
+		{"%Y-%m-%d", "2019-02-30"},
+		{"%Y-%m-%d", "2019-02-03 extra"},
+		{"%Y %j", "2019 366"},
+		{"%H %p", "10 XM"},
+		{"%Q", "1"},
+		{"%b", "Foo"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.s, func(t *testing.T) {
+			_, err := Parse(test.pattern, test.s)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,12 +2,12 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"regexp"
 
 	"github.com/jacobsimpson/jt/ast"
 	"github.com/jacobsimpson/jt/debug"
@@ -21,9 +21,6 @@ import (
 
 const VERSION = "0.0.1"
 
-// The regexp to use for splitting input lines into columns.
-var defaultSplit = regexp.MustCompile("[[:blank:]]+")
-
 func execName() string {
 	return filepath.Base(os.Args[0])
 }
@@ -48,6 +45,7 @@ func main() {
 	var inputFiles []string
 	var version bool
 	var verbose int
+	var bytes bool
 
 	flag.CountVarP(&verbose, "verbose", "v",
 		"increase output for debugging purposes")
@@ -55,6 +53,8 @@ func main() {
 		"add the script to the commands to be execute")
 	flag.StringVarP(&scriptFile, "file", "f", scriptFile,
 		"add the contents of script-file to the commands to be execute")
+	flag.BoolVarP(&bytes, "bytes", "b", bytes,
+		"count bytes rather than characters in substrings and lengths")
 	flag.BoolVar(&version, "version", version,
 		"output version information and exit")
 	flag.Parse()
@@ -83,8 +83,10 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := execute(rules, inputFiles); err != nil {
+	if err := execute(rules, inputFiles, bytes); err != nil {
 		switch e := err.(type) {
+		case *ast.ExitError:
+			os.Exit(e.Code)
 		case parser.ErrorLister:
 			fmt.Fprintf(os.Stderr, "Could not understand program:\n")
 			for _, err := range e.Errors() {
@@ -98,30 +100,72 @@ func main() {
 	}
 }
 
-func execute(rules string, inputFiles []string) error {
+func execute(rules string, inputFiles []string, bytes bool) error {
 	var result error
 
-	ast, err := parse(rules)
+	// The same environment is used for every line of every file, so that
+	// variables keep their values from one line to the next.
+	environment := ast.NewEnvironment()
+	environment.Bytes = bytes
+
+	program, err := parse(rules)
 	if err != nil {
 		return err
 	}
 
-	debug.Debug("ast = %s\n", ast)
+	debug.Debug("ast = %s\n", program)
+
+	environment.Functions = program.Functions
+
+	// An exit in a BEGIN block, or in a rule, stops the input from being
+	// read, but the END blocks are still executed.
+	var exit *ast.ExitError
+	for _, block := range program.Begin {
+		if err := executeBlock(block, environment); errors.As(err, &exit) {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
 
-	if len(inputFiles) == 0 {
-		return processReader(ast, os.Stdin)
+	if exit != nil {
+		result = exit
+	} else if len(inputFiles) == 0 {
+		result = processReader(program, environment, os.Stdin)
 	} else {
 		for _, f := range inputFiles {
-			if err := processFile(ast, f); err != nil {
+			if err := processFile(program, environment, f); errors.As(err, &exit) {
+				result = exit
+				break
+			} else if err != nil {
 				result = err
 			}
 		}
 	}
 
+	// The environment still holds the last line of input, so END blocks can
+	// refer to it.
+	for _, block := range program.End {
+		if err := executeBlock(block, environment); errors.As(err, &exit) {
+			return exit
+		} else if err != nil {
+			return err
+		}
+	}
+
 	return result
 }
 
-func processFile(interpreter *ast.Program, fileName string) error {
+// executeBlock executes a BEGIN or END block. There is no line for a next to
+// skip, so a next just ends the block.
+func executeBlock(block *ast.Block, environment *ast.Environment) error {
+	if err := block.Execute(environment); err != nil && !errors.Is(err, ast.ErrNext) {
+		return err
+	}
+	return nil
+}
+
+func processFile(interpreter *ast.Program, environment *ast.Environment, fileName string) error {
 	f, err := os.Open(fileName)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s: can't read %s: No such file or directory", execName(), fileName)
@@ -129,51 +173,53 @@ func processFile(interpreter *ast.Program, fileName string) error {
 	}
 	defer f.Close()
 
-	return processReader(interpreter, f)
+	return processReader(interpreter, environment, f)
 }
 
-func processReader(interpreter *ast.Program, reader io.Reader) error {
+func processReader(interpreter *ast.Program, environment *ast.Environment, reader io.Reader) error {
 	scanner := bufio.NewScanner(reader)
 
-	lineNumber := 0
+	lineNumber := 1
 	for scanner.Scan() {
-		if applyRules(interpreter, scanner.Text(), lineNumber) {
+		if err := applyRules(interpreter, environment, scanner.Text(), lineNumber); err != nil {
+			return err
 		}
 		lineNumber++
 	}
 	return nil
 }
 
-func applyRules(interp *ast.Program, line string, lineNumber int) bool {
-	columns := []string{line}
-	for i, c := range defaultSplit.Split(line, -1) {
-		if !(i == 0 && c == "") {
-			columns = append(columns, c)
-		}
-	}
-	environment := &ast.Environment{
-		Row: &ast.Row{
-			LineNumber: lineNumber,
-			Columns:    columns,
-		},
-	}
+// applyRules applies each of the rules to a line. A next stops the rules
+// from being applied to the rest of the line, and an exit is returned, so
+// that no more lines are read.
+func applyRules(interp *ast.Program, environment *ast.Environment, line string, lineNumber int) error {
+	environment.Row = ast.NewRow(lineNumber, line)
 
 	debug.Debug("Line %d splits as %+v", lineNumber, environment)
 
 	debug.Info("There are %d rules", len(interp.Rules))
 	for _, rule := range interp.Rules {
 		debug.Info("    Evaluating: %s\n", rule)
+		var exit *ast.ExitError
 		result, err := rule.Evaluate(environment)
-		if err != nil {
+		if errors.Is(err, ast.ErrNext) {
+			return nil
+		} else if errors.As(err, &exit) {
+			return exit
+		} else if err != nil {
 			fmt.Fprintf(os.Stderr, "Could not evaluate %q: %v", rule, err)
 		} else if b, ok := result.(bool); ok && b {
 			debug.Info("        Executing block\n")
-			if err := rule.Execute(environment); err != nil {
+			if err := rule.Execute(environment); errors.Is(err, ast.ErrNext) {
+				return nil
+			} else if errors.As(err, &exit) {
+				return exit
+			} else if err != nil {
 				fmt.Fprintf(os.Stderr, "## Found some errors.\n")
 				fmt.Fprintf(os.Stderr, "%v\n", err)
 				os.Exit(1)
 			}
 		}
 	}
-	return true
+	return nil
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jacobsimpson/jt/ast"
 	"github.com/jacobsimpson/jt/parser"
@@ -17,7 +18,7 @@ func TestParser(t *testing.T) {
 	}{
 		{
 			"%1>9",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%1"),
@@ -31,7 +32,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%1<0x03",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%1"),
@@ -45,7 +46,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" %1 == 0x03     ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%1"),
@@ -59,7 +60,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" %99 == 0b0110     ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%99"),
@@ -73,7 +74,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" %19 == 0b01_10     ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%19"),
@@ -87,7 +88,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%2 <= 0b00_00_10_00",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%2"),
@@ -101,7 +102,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" %0   ==  /things/ ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -115,7 +116,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" %1   ==  2014-09-12T ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%1"),
@@ -129,7 +130,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" >=0o723 ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -143,7 +144,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" %1   >=  13.45 ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%1"),
@@ -157,7 +158,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%0 == /things/ { print(%0) }",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -171,7 +172,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%0 == /things/ { notarealfunc(%2) }",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -179,7 +180,7 @@ func TestParser(t *testing.T) {
 						Right:    mustNewRegexpValue(t, "things"),
 					},
 					&ast.Block{
-						Commands: []*ast.Command{
+						Statements: []ast.Expression{
 							&ast.Command{
 								Name:       "notarealfunc",
 								Parameters: []ast.Expression{ast.NewVarValue("%2")},
@@ -192,7 +193,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"/things/ { print(%2) }",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -200,7 +201,7 @@ func TestParser(t *testing.T) {
 						Right:    mustNewRegexpValue(t, "things"),
 					},
 					&ast.Block{
-						Commands: []*ast.Command{
+						Statements: []ast.Expression{
 							&ast.Command{
 								Name:       "print",
 								Parameters: []ast.Expression{ast.NewVarValue("%2")},
@@ -213,7 +214,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" %9 == -3     ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%9"),
@@ -227,7 +228,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"/things/ { print(%2[3:7]) }",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -235,14 +236,14 @@ func TestParser(t *testing.T) {
 						Right:    mustNewRegexpValue(t, "things"),
 					},
 					&ast.Block{
-						Commands: []*ast.Command{
+						Statements: []ast.Expression{
 							&ast.Command{
 								Name: "print",
 								Parameters: []ast.Expression{
 									&ast.RangeExpression{
-										ast.NewVarValue("%2"),
-										func(i int) *int { return &i }(3),
-										func(i int) *int { return &i }(7),
+										Expression: ast.NewVarValue("%2"),
+										Start:      func(i int) *int { return &i }(3),
+										End:        func(i int) *int { return &i }(7),
 									},
 								},
 							},
@@ -254,7 +255,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"/things/ { print(%2[-3:]) }",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -262,14 +263,13 @@ func TestParser(t *testing.T) {
 						Right:    mustNewRegexpValue(t, "things"),
 					},
 					&ast.Block{
-						Commands: []*ast.Command{
+						Statements: []ast.Expression{
 							&ast.Command{
 								Name: "print",
 								Parameters: []ast.Expression{
 									&ast.RangeExpression{
-										ast.NewVarValue("%2"),
-										func(i int) *int { return &i }(-3),
-										nil,
+										Expression: ast.NewVarValue("%2"),
+										Start:      func(i int) *int { return &i }(-3),
 									},
 								},
 							},
@@ -281,7 +281,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"1.0 < %3 <= 2.4",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.AndComparison{
 						&ast.Comparison{
@@ -302,7 +302,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"3.0 >= %4 > 2.4",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.AndComparison{
 						&ast.Comparison{
@@ -329,12 +329,12 @@ func TestParser(t *testing.T) {
 				1,
 				1,
 				0,
-				"test:1:1 (0): rule three_term_boolean_expression",
+				"test:1:1 (0): rule comparison_expression",
 			)}),
 		},
 		//{
 		//	" %3 == +6786     ",
-		//	&ast.Program{[]*ast.Rule{
+		//	&ast.Program{Rules: []*ast.Rule{
 		//		&ast.Rule{
 		//			&ast.Comparison{
 		//				Left:     ast.NewVarValue("%3"),
@@ -348,7 +348,7 @@ func TestParser(t *testing.T) {
 		//},
 		{
 			"<9",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -362,7 +362,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"==/this/",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -376,7 +376,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"/this/",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -390,7 +390,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"<2020-01-01T",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -404,7 +404,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			">2020-01-01T",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -418,7 +418,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%3 == $a1",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%3"),
@@ -432,7 +432,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%2 == today",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%2"),
@@ -451,7 +451,7 @@ func TestParser(t *testing.T) {
 
 
 			`,
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%112"),
@@ -465,7 +465,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%2 < tomorrow",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%2"),
@@ -479,7 +479,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			`%3 == "this is the thing"`,
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%3"),
@@ -493,7 +493,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			`   ==      "this is the thing"   `,
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -507,7 +507,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			`'this is the thing'   `,
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -521,7 +521,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			" `this is the thing`   ",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%0"),
@@ -535,7 +535,7 @@ func TestParser(t *testing.T) {
 		},
 		{
 			"%3 == 2.4",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left:     ast.NewVarValue("%3"),
@@ -547,29 +547,1138 @@ func TestParser(t *testing.T) {
 			}},
 			nil,
 		},
-		//{
-		//	"%3 in {1, 3, 5}",
-		//	&ast.Program{[]*ast.Rule{
-		//		&ast.Rule{
-		//			&ast.Comparison{
-		//				Left:     ast.NewVarValue("%0"),
-		//				Operator: ast.LT_Operator,
-		//				Right:    ast.NewIntegerValue("9", 9),
-		//			},
-		//			ast.NewPrintlnBlock(),
-		//		},
-		//	}},
-		//nil,
-		//},
+		{
+			"/ERROR/ { print(%1) } /WARN/ { print(%2) }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewRegexpValue(t, "ERROR"),
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{ast.NewVarValue("%1")}),
+						},
+					},
+				},
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewRegexpValue(t, "WARN"),
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{ast.NewVarValue("%2")}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"%1 > 3; %2 < 4",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%1"),
+						Operator: ast.GT_Operator,
+						Right:    ast.NewIntegerValue("3", 3),
+					},
+					ast.NewPrintlnBlock(),
+				},
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%2"),
+						Operator: ast.LT_Operator,
+						Right:    ast.NewIntegerValue("4", 4),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`
+			/ERROR/
+			/WARN/ { print(%2) }
+			`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewRegexpValue(t, "ERROR"),
+					},
+					ast.NewPrintlnBlock(),
+				},
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewRegexpValue(t, "WARN"),
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{ast.NewVarValue("%2")}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`%3 < 12 and %4 == "joe" or not /x/`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.OrComparison{
+						Left: &ast.AndComparison{
+							Left: &ast.Comparison{
+								Left:     ast.NewVarValue("%3"),
+								Operator: ast.LT_Operator,
+								Right:    ast.NewIntegerValue("12", 12),
+							},
+							Right: &ast.Comparison{
+								Left:     ast.NewVarValue("%4"),
+								Operator: ast.EQ_Operator,
+								Right:    ast.NewStringValue(`"joe"`),
+							},
+						},
+						Right: ast.NewNegativeExpression(&ast.Comparison{
+							Left:     ast.NewVarValue("%0"),
+							Operator: ast.EQ_Operator,
+							Right:    mustNewRegexpValue(t, "x"),
+						}),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`!(%3 < 12 || %4 == "joe") && >=5`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.AndComparison{
+						Left: ast.NewNegativeExpression(&ast.OrComparison{
+							Left: &ast.Comparison{
+								Left:     ast.NewVarValue("%3"),
+								Operator: ast.LT_Operator,
+								Right:    ast.NewIntegerValue("12", 12),
+							},
+							Right: &ast.Comparison{
+								Left:     ast.NewVarValue("%4"),
+								Operator: ast.EQ_Operator,
+								Right:    ast.NewStringValue(`"joe"`),
+							},
+						}),
+						Right: &ast.Comparison{
+							Left:     ast.NewVarValue("%0"),
+							Operator: ast.GE_Operator,
+							Right:    ast.NewIntegerValue("5", 5),
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"{ print(%1, %3[:-4]); print(%2) }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{
+								ast.NewVarValue("%1"),
+								ast.NewRangeExpression(
+									ast.NewVarValue("%3"),
+									nil,
+									func(i int) *int { return &i }(-4)),
+							}),
+							ast.NewPrintCommand([]ast.Expression{ast.NewVarValue("%2")}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`/x/ {
+				println(%1 > 3 and %2 == "a", (%3))
+				println();
+			}`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewRegexpValue(t, "x"),
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								&ast.AndComparison{
+									Left: &ast.Comparison{
+										Left:     ast.NewVarValue("%1"),
+										Operator: ast.GT_Operator,
+										Right:    ast.NewIntegerValue("3", 3),
+									},
+									Right: &ast.Comparison{
+										Left:     ast.NewVarValue("%2"),
+										Operator: ast.EQ_Operator,
+										Right:    ast.NewStringValue(`"a"`),
+									},
+								},
+								ast.NewVarValue("%3"),
+							}),
+							ast.NewPrintlnCommand(nil),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"%2 > last { println(%1, last) }; { last = %2 }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%2"),
+						Operator: ast.GT_Operator,
+						Right:    ast.NewVarValue("last"),
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								ast.NewVarValue("%1"),
+								ast.NewVarValue("last"),
+							}),
+						},
+					},
+				},
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							&ast.Assignment{
+								Name:  "last",
+								Value: ast.NewVarValue("%2"),
+							},
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"{ nowish = today; isNew = nowish == %3 }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							&ast.Assignment{
+								Name:  "nowish",
+								Value: ast.NewKeywordValue("today"),
+							},
+							&ast.Assignment{
+								Name: "isNew",
+								Value: &ast.Comparison{
+									Left:     ast.NewVarValue("nowish"),
+									Operator: ast.EQ_Operator,
+									Right:    ast.NewVarValue("%3"),
+								},
+							},
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`BEGIN { println("start") }
+			/ERROR/ { last = %2 }
+			END { println(last) }`,
+			&ast.Program{
+				Begin: []*ast.Block{
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{ast.NewStringValue(`"start"`)}),
+						},
+					},
+				},
+				Rules: []*ast.Rule{
+					&ast.Rule{
+						&ast.Comparison{
+							Left:     ast.NewVarValue("%0"),
+							Operator: ast.EQ_Operator,
+							Right:    mustNewRegexpValue(t, "ERROR"),
+						},
+						&ast.Block{
+							Statements: []ast.Expression{
+								&ast.Assignment{
+									Name:  "last",
+									Value: ast.NewVarValue("%2"),
+								},
+							},
+						},
+					},
+				},
+				End: []*ast.Block{
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{ast.NewVarValue("last")}),
+						},
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"/Results/ -> /^$/ { print(%1) }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.RangeSelection{
+						Start: &ast.Comparison{
+							Left:     ast.NewVarValue("%0"),
+							Operator: ast.EQ_Operator,
+							Right:    mustNewRegexpValue(t, "Results"),
+						},
+						End: &ast.Comparison{
+							Left:     ast.NewVarValue("%0"),
+							Operator: ast.EQ_Operator,
+							Right:    mustNewRegexpValue(t, "^$"),
+						},
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{ast.NewVarValue("%1")}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"10 ~> %1 >= 2020-01-01T",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.RangeSelection{
+						Start: &ast.Comparison{
+							Left:     ast.NewVarValue("%#"),
+							Operator: ast.EQ_Operator,
+							Right:    ast.NewIntegerValue("10", 10),
+						},
+						End: &ast.Comparison{
+							Left:     ast.NewVarValue("%1"),
+							Operator: ast.GE_Operator,
+							Right:    mustNewDateTimeValue(t, "2020-01-01T"),
+						},
+						Exclusive: true,
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"%3 * 1.5 > 10",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left: &ast.Arithmetic{
+							Left:     ast.NewVarValue("%3"),
+							Operator: ast.MUL_Operator,
+							Right:    mustNewDoubleFromString(t, "1.5"),
+						},
+						Operator: ast.GT_Operator,
+						Right:    ast.NewIntegerValue("10", 10),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"{ print(%3 + 10 * 2 - 1, 2 ^ 3 ^ 2, -%1 \\ 2 % 3) }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{
+								&ast.Arithmetic{
+									Left: &ast.Arithmetic{
+										Left:     ast.NewVarValue("%3"),
+										Operator: ast.ADD_Operator,
+										Right: &ast.Arithmetic{
+											Left:     ast.NewIntegerValue("10", 10),
+											Operator: ast.MUL_Operator,
+											Right:    ast.NewIntegerValue("2", 2),
+										},
+									},
+									Operator: ast.SUB_Operator,
+									Right:    ast.NewIntegerValue("1", 1),
+								},
+								&ast.Arithmetic{
+									Left:     ast.NewIntegerValue("2", 2),
+									Operator: ast.POW_Operator,
+									Right: &ast.Arithmetic{
+										Left:     ast.NewIntegerValue("3", 3),
+										Operator: ast.POW_Operator,
+										Right:    ast.NewIntegerValue("2", 2),
+									},
+								},
+								&ast.Arithmetic{
+									Left: &ast.Arithmetic{
+										Left:     ast.NewNegationExpression(ast.NewVarValue("%1")),
+										Operator: ast.INTDIV_Operator,
+										Right:    ast.NewIntegerValue("2", 2),
+									},
+									Operator: ast.MOD_Operator,
+									Right:    ast.NewIntegerValue("3", 3),
+								},
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"%5 > now - 5M",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%5"),
+						Operator: ast.GT_Operator,
+						Right: &ast.Arithmetic{
+							Left:     ast.NewKeywordValue("now"),
+							Operator: ast.SUB_Operator,
+							Right:    mustNewDurationValue(t, "5M"),
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"{ print(-1Y2M3D, -5) }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{
+								ast.NewNegationExpression(mustNewDurationValue(t, "1Y2M3D")),
+								ast.NewIntegerValue("-5", -5),
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"==2013T",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewDateTimeValue(t, "2013T"),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"2018-02-12T14:02 < %1 < 20180212T14:02:01.500Z",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.AndComparison{
+						Left: &ast.Comparison{
+							Left:     mustNewDateTimeValue(t, "2018-02-12T14:02"),
+							Operator: ast.LT_Operator,
+							Right:    ast.NewVarValue("%1"),
+						},
+						Right: &ast.Comparison{
+							Left:     ast.NewVarValue("%1"),
+							Operator: ast.LT_Operator,
+							Right:    mustNewDateTimeValue(t, "20180212T14:02:01.500Z"),
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"%1 < 2013-06T-1M",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%1"),
+						Operator: ast.LT_Operator,
+						Right: &ast.Arithmetic{
+							Left:     mustNewDateTimeValue(t, "2013-06T"),
+							Operator: ast.SUB_Operator,
+							Right:    mustNewDurationValue(t, "1M"),
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`{ println(format(%5, "%Y-%m-%dT")) }`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								&ast.Command{
+									Name: "format",
+									Parameters: []ast.Expression{
+										ast.NewVarValue("%5"),
+										ast.NewStringValue(`"%Y-%m-%dT"`),
+									},
+								},
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`{ print(%1[-"/"+:"."], %2[:/txt/]) }`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintCommand([]ast.Expression{
+								&ast.RangeExpression{
+									Expression: ast.NewVarValue("%1"),
+									StartMarker: &ast.RangeMarker{
+										Pattern:  ast.NewStringValue(`"/"`),
+										Backward: true,
+										After:    true,
+									},
+									EndMarker: &ast.RangeMarker{
+										Pattern: ast.NewStringValue(`"."`),
+									},
+								},
+								&ast.RangeExpression{
+									Expression: ast.NewVarValue("%2"),
+									EndMarker: &ast.RangeMarker{
+										Pattern: mustNewRegexpValue(t, "txt"),
+									},
+								},
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`{ println(%2.trim().lower(), $PATH.split(":").join(" ")) }`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								&ast.Command{
+									Name:   "lower",
+									Method: true,
+									Parameters: []ast.Expression{
+										&ast.Command{
+											Name:       "trim",
+											Method:     true,
+											Parameters: []ast.Expression{ast.NewVarValue("%2")},
+										},
+									},
+								},
+								&ast.Command{
+									Name:   "join",
+									Method: true,
+									Parameters: []ast.Expression{
+										&ast.Command{
+											Name:   "split",
+											Method: true,
+											Parameters: []ast.Expression{
+												ast.NewVarValue("$PATH"),
+												ast.NewStringValue(`":"`),
+											},
+										},
+										ast.NewStringValue(`" "`),
+									},
+								},
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`%1[:-"."].endswith("_test") and %2.contains(/fail/)`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.AndComparison{
+						Left: &ast.Command{
+							Name:   "endswith",
+							Method: true,
+							Parameters: []ast.Expression{
+								&ast.RangeExpression{
+									Expression: ast.NewVarValue("%1"),
+									EndMarker: &ast.RangeMarker{
+										Pattern:  ast.NewStringValue(`"."`),
+										Backward: true,
+									},
+								},
+								ast.NewStringValue(`"_test"`),
+							},
+						},
+						Right: &ast.Command{
+							Name:   "contains",
+							Method: true,
+							Parameters: []ast.Expression{
+								ast.NewVarValue("%2"),
+								mustNewRegexpValue(t, "fail"),
+							},
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`"abc".upper() == %1`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left: &ast.Command{
+							Name:       "upper",
+							Method:     true,
+							Parameters: []ast.Expression{ast.NewStringValue(`"abc"`)},
+						},
+						Operator: ast.EQ_Operator,
+						Right:    ast.NewVarValue("%1"),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`{ println(%2[:-1] |> trim() |> replace("-", $SEP)) }`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								&ast.Command{
+									Name: "replace",
+									Parameters: []ast.Expression{
+										&ast.Command{
+											Name: "trim",
+											Parameters: []ast.Expression{
+												&ast.RangeExpression{
+													Expression: ast.NewVarValue("%2"),
+													End:        func(i int) *int { return &i }(-1),
+												},
+											},
+										},
+										ast.NewStringValue(`"-"`),
+										ast.NewVarValue("$SEP"),
+									},
+								},
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`%1 + 1 |> len() == 2`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left: &ast.Command{
+							Name: "len",
+							Parameters: []ast.Expression{
+								&ast.Arithmetic{
+									Left:     ast.NewVarValue("%1"),
+									Operator: ast.ADD_Operator,
+									Right:    ast.NewIntegerValue("1", 1),
+								},
+							},
+						},
+						Operator: ast.EQ_Operator,
+						Right:    ast.NewIntegerValue("2", 2),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`%1 |> startswith("#")`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Command{
+						Name: "startswith",
+						Parameters: []ast.Expression{
+							ast.NewVarValue("%1"),
+							ast.NewStringValue(`"#"`),
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`{ println("%1 --> %2 %3[:-2]", "${%4.upper()}", '%1', "100%") }`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								&ast.InterpolatedString{Parts: []ast.Expression{
+									ast.NewVarValue("%1"),
+									ast.NewStringValue(`" --> "`),
+									ast.NewVarValue("%2"),
+									ast.NewStringValue(`" "`),
+									&ast.RangeExpression{
+										Expression: ast.NewVarValue("%3"),
+										End:        func(i int) *int { return &i }(-2),
+									},
+								}},
+								&ast.InterpolatedString{Parts: []ast.Expression{
+									&ast.Command{
+										Name:       "upper",
+										Method:     true,
+										Parameters: []ast.Expression{ast.NewVarValue("%4")},
+									},
+								}},
+								ast.NewStringValue(`'%1'`),
+								ast.NewStringValue(`"100%"`),
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			`%1 == "%2"`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%1"),
+						Operator: ast.EQ_Operator,
+						Right: &ast.InterpolatedString{Parts: []ast.Expression{
+							ast.NewVarValue("%2"),
+						}},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"{ println(\"%1\\t\\\"%2\\\"\", 'a\\tb', `a\\tb`) }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								&ast.InterpolatedString{Parts: []ast.Expression{
+									ast.NewVarValue("%1"),
+									mustNewEscapedStringValue(t, `"\t\""`),
+									ast.NewVarValue("%2"),
+									mustNewEscapedStringValue(t, `"\""`),
+								}},
+								ast.NewStringValue(`'a\tb'`),
+								ast.NewStringValue("`a\\tb`"),
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"%2",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					ast.NewVarValue("%2"),
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"true",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					ast.NewBooleanValue(true),
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"(%1 > 5) == false",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left: &ast.Comparison{
+							Left:     ast.NewVarValue("%1"),
+							Operator: ast.GT_Operator,
+							Right:    ast.NewIntegerValue("5", 5),
+						},
+						Operator: ast.EQ_Operator,
+						Right:    ast.NewBooleanValue(false),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			`{ println(%3?.trim().upper() ?? "none") }`,
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							ast.NewPrintlnCommand([]ast.Expression{
+								&ast.Coalesce{
+									Left: &ast.Command{Name: "upper", Method: true, Parameters: []ast.Expression{
+										&ast.Command{Name: "trim", Method: true, Parameters: []ast.Expression{
+											ast.NewVarValue("%3"),
+										}},
+									}},
+									Right: ast.NewStringValue(`"none"`),
+								},
+							}),
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"%3 ?? 0 > 5",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left: &ast.Coalesce{
+							Left:  ast.NewVarValue("%3"),
+							Right: ast.NewIntegerValue("0", 0),
+						},
+						Operator: ast.GT_Operator,
+						Right:    ast.NewIntegerValue("5", 5),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"%3 != null",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%3"),
+						Operator: ast.NE_Operator,
+						Right:    ast.NewNullValue(),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"/a/ { x = %2; %1.upper() }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewRegexpValue(t, "a"),
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							&ast.Assignment{Name: "x", Value: ast.NewVarValue("%2")},
+							&ast.Command{Name: "upper", Method: true, Parameters: []ast.Expression{
+								ast.NewVarValue("%1"),
+							}},
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"function label(s string, n) { \"${s}: ${n}\" }\n{ %1.label(%2) }",
+			&ast.Program{
+				Functions: map[string]*ast.Function{
+					"label": &ast.Function{
+						Name: "label",
+						Parameters: []*ast.Parameter{
+							&ast.Parameter{Name: "s", Type: "string"},
+							&ast.Parameter{Name: "n"},
+						},
+						Body: &ast.Block{
+							Statements: []ast.Expression{
+								&ast.InterpolatedString{Parts: []ast.Expression{
+									ast.NewVarValue("s"),
+									mustNewEscapedStringValue(t, `": "`),
+									ast.NewVarValue("n"),
+								}},
+							},
+						},
+					},
+				},
+				Rules: []*ast.Rule{
+					&ast.Rule{
+						nil,
+						&ast.Block{
+							Statements: []ast.Expression{
+								&ast.Command{Name: "label", Method: true, Parameters: []ast.Expression{
+									ast.NewVarValue("%1"),
+									ast.NewVarValue("%2"),
+								}},
+							},
+						},
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"/^#/ { next }\n{\n\tfor p in %1.split(\",\") { if p == \"x\" { exit 2 } else { println(p) } }\n\twhile false { exit }\n}",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%0"),
+						Operator: ast.EQ_Operator,
+						Right:    mustNewRegexpValue(t, "^#"),
+					},
+					&ast.Block{Statements: []ast.Expression{&ast.Next{}}},
+				},
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							&ast.For{
+								Name: "p",
+								List: &ast.Command{Name: "split", Method: true, Parameters: []ast.Expression{
+									ast.NewVarValue("%1"),
+									mustNewEscapedStringValue(t, `","`),
+								}},
+								Body: &ast.Block{Statements: []ast.Expression{
+									&ast.If{
+										Condition: &ast.Comparison{
+											Left:     ast.NewVarValue("p"),
+											Operator: ast.EQ_Operator,
+											Right:    mustNewEscapedStringValue(t, `"x"`),
+										},
+										Then: &ast.Block{Statements: []ast.Expression{
+											&ast.Exit{Code: ast.NewIntegerValue("2", 2)},
+										}},
+										Else: &ast.Block{Statements: []ast.Expression{
+											ast.NewPrintlnCommand([]ast.Expression{ast.NewVarValue("p")}),
+										}},
+									},
+								}},
+							},
+							&ast.While{
+								Condition: ast.NewBooleanValue(false),
+								Body:      &ast.Block{Statements: []ast.Expression{&ast.Exit{}}},
+							},
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"{ %3 = %3 + 10; delete %-1\n append %1; print(%0) }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							&ast.Assignment{
+								Name: "%3",
+								Value: &ast.Arithmetic{
+									Left:     ast.NewVarValue("%3"),
+									Operator: ast.ADD_Operator,
+									Right:    ast.NewIntegerValue("10", 10),
+								},
+							},
+							&ast.DeleteColumn{Column: "%-1"},
+							&ast.AppendColumn{Value: ast.NewVarValue("%1")},
+							&ast.Command{
+								Name:       "print",
+								Parameters: []ast.Expression{ast.NewVarValue("%0")},
+							},
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"%3 in {1, 3, 5}",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%3"),
+						Operator: ast.IN_Operator,
+						Right: ast.NewSetValue([]ast.Value{
+							ast.NewIntegerValue("1", 1),
+							ast.NewIntegerValue("3", 3),
+							ast.NewIntegerValue("5", 5),
+						}),
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"%3 in 10..20 and %5 in 2024-01-01T..%6",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.AndComparison{
+						&ast.Comparison{
+							Left:     ast.NewVarValue("%3"),
+							Operator: ast.IN_Operator,
+							Right: ast.NewIntervalValue(
+								ast.NewIntegerValue("10", 10),
+								ast.NewIntegerValue("20", 20),
+							),
+						},
+						&ast.Comparison{
+							Left:     ast.NewVarValue("%5"),
+							Operator: ast.IN_Operator,
+							Right: &ast.IntervalLiteral{
+								Start: mustNewDateTimeValue(t, "2024-01-01T"),
+								End:   ast.NewVarValue("%6"),
+							},
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"%1 in lines('allowlist.txt')",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left:     ast.NewVarValue("%1"),
+						Operator: ast.IN_Operator,
+						Right: &ast.Command{
+							Name:       "lines",
+							Parameters: []ast.Expression{ast.NewStringValue("'allowlist.txt'")},
+						},
+					},
+					ast.NewPrintlnBlock(),
+				},
+			}},
+			nil,
+		},
+		{
+			"<%1, %2> in pairs { %0.split(',')[-1] }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					&ast.Comparison{
+						Left: &ast.TupleLiteral{Elements: []ast.Expression{
+							ast.NewVarValue("%1"),
+							ast.NewVarValue("%2"),
+						}},
+						Operator: ast.IN_Operator,
+						Right:    ast.NewVarValue("pairs"),
+					},
+					&ast.Block{
+						Statements: []ast.Expression{
+							&ast.Index{
+								Collection: &ast.Command{
+									Name:   "split",
+									Method: true,
+									Parameters: []ast.Expression{
+										ast.NewVarValue("%0"),
+										ast.NewStringValue("','"),
+									},
+								},
+								Index: ast.NewIntegerValue("-1", -1),
+							},
+						},
+					},
+				},
+			}},
+			nil,
+		},
+		{
+			"{ m = {\"one\": [1, 2], 'two': [%1]} }",
+			&ast.Program{Rules: []*ast.Rule{
+				&ast.Rule{
+					nil,
+					&ast.Block{
+						Statements: []ast.Expression{
+							&ast.Assignment{
+								Name: "m",
+								Value: &ast.MapLiteral{
+									Keys: []ast.Expression{
+										ast.NewStringValue(`"one"`),
+										ast.NewStringValue("'two'"),
+									},
+									Values: []ast.Expression{
+										ast.NewListValue([]ast.Value{
+											ast.NewIntegerValue("1", 1),
+											ast.NewIntegerValue("2", 2),
+										}),
+										&ast.ListLiteral{Elements: []ast.Expression{ast.NewVarValue("%1")}},
+									},
+								},
+							},
+						},
+					},
+				},
+			}},
+			nil,
+		},
 		{
 			"%3[-4:] == '.txt'",
-			&ast.Program{[]*ast.Rule{
+			&ast.Program{Rules: []*ast.Rule{
 				&ast.Rule{
 					&ast.Comparison{
 						Left: &ast.RangeExpression{
-							ast.NewVarValue("%3"),
-							func(i int) *int { return &i }(-4),
-							nil,
+							Expression: ast.NewVarValue("%3"),
+							Start:      func(i int) *int { return &i }(-4),
 						},
 						Operator: ast.EQ_Operator,
 						Right:    ast.NewStringValue("'.txt'"),
@@ -593,6 +1702,39 @@ func TestParser(t *testing.T) {
 	}
 }
 
+// Each level of parentheses used to multiply the time it took to parse a
+// program, so deeply nested programs have to parse in well under a second.
+func TestParserNesting(t *testing.T) {
+	tests := []string{
+		"((((%1 > 1))))",
+		"(((((%1 > 1) and %2 < 3) or %3 == 4) and %4 != 5))",
+		"not (not (not (not (%1 > 1))))",
+		"((((%1)))) == 5",
+		"((((%1 + 1) * 2) - 3) / 4) > 5",
+		"{ println((((%1)))) }",
+		"{ println(lower(trim(upper(trim(%1))))) }",
+		"{ x = (%1 + (%2 * (%3 + (%4 ^ (%5 - 1))))) }",
+		`{ println(((((%1.trim()).split(","))[1]).upper())) }`,
+	}
+
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			done := make(chan error)
+			go func() {
+				_, err := parse(test)
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				assert.Nil(t, err)
+			case <-time.After(time.Second):
+				t.Fatalf("parsing %q took more than a second", test)
+			}
+		})
+	}
+}
+
 func mustNewRegexpValue(t *testing.T, value string) ast.Value {
 	v, err := ast.NewRegexpValue(value)
 	if err != nil {
@@ -609,6 +1751,22 @@ func mustNewDateTimeValue(t *testing.T, value string) ast.Value {
 	return v
 }
 
+func mustNewEscapedStringValue(t *testing.T, value string) ast.Value {
+	v, err := ast.NewEscapedStringValue(value)
+	if err != nil {
+		t.Fatalf("Unable to convert %q to a value", value)
+	}
+	return v
+}
+
+func mustNewDurationValue(t *testing.T, value string) ast.Value {
+	v, err := ast.NewDurationValue(value)
+	if err != nil {
+		t.Fatalf("Unable to convert %q to a value", value)
+	}
+	return v
+}
+
 func mustNewDoubleFromString(t *testing.T, value string) ast.Value {
 	v, err := ast.NewDoubleFromString(value)
 	if err != nil {
@@ -619,6 +1777,6 @@ func mustNewDoubleFromString(t *testing.T, value string) ast.Value {
 
 func newPrintBlock() *ast.Block {
 	return &ast.Block{
-		[]*ast.Command{ast.NewPrintCommand([]ast.Expression{ast.NewVarValue("%0")})},
+		[]ast.Expression{ast.NewPrintCommand([]ast.Expression{ast.NewVarValue("%0")})},
 	}
 }